@@ -19,15 +19,18 @@ package client
 import (
 	"context"
 	"crypto/tls"
+	"strings"
 	"time"
 
 	"github.com/gravitational/reporting"
 	"github.com/gravitational/reporting/types"
 
 	"github.com/gravitational/trace"
+	"github.com/gravitational/trace/trail"
 	log "github.com/sirupsen/logrus"
 	grpcapi "google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
 )
 
 // ClientConfig defines the reporting client config
@@ -40,16 +43,94 @@ type ClientConfig struct {
 	Certificate tls.Certificate
 	// Insecure is whether the client should skip server cert verification
 	Insecure bool
+	// ResourceVersion is the resource version to send events in, by
+	// default the current version is used and the client falls back to
+	// older versions if the server does not support it
+	ResourceVersion string
+	// SigningKey is the optional installation key events are signed with
+	SigningKey *types.SigningKey
+	// PseudonymSecretPath is the optional path to the file with the
+	// installation secret, if it is set identifiers of recorded events
+	// are replaced with pseudonyms keyed with the secret, the secret is
+	// generated if the file does not exist
+	PseudonymSecretPath string
+	// Disabled disables reporting, the opt-out is recorded with a single
+	// consent event if the consent state can be saved, the DisableEnvVar
+	// environment variable overrides it
+	Disabled bool
+	// ConsentStatePath is the optional path to the file the reported
+	// consent is saved to, so that the opt-out is recorded once rather
+	// than on every client start, by default the file is saved next to
+	// the pseudonym secret if PseudonymSecretPath is set. Clients that
+	// start disabled without a consent state do not record the opt-out
+	ConsentStatePath string
+	// AllowEvents is an optional list of names of events that are
+	// reported, other events are dropped
+	AllowEvents []string
+	// DenyEvents is an optional list of names of events that are dropped
+	DenyEvents []string
+	// TransparencyLog is the optional configuration of the local log of
+	// all event batches successfully sent to the server
+	TransparencyLog *TransparencyLogConfig
+	// Validation defines the rules recorded events are validated with
+	// before they are sent, unset rules use default values
+	Validation types.ValidationConfig
 }
 
 // Client defines the reporting client interface
 type Client interface {
 	// Record records an event
 	Record(types.Event)
+	// SetConsent grants or revokes consent to report events
+	SetConsent(granted bool)
 }
 
 // NewClient returns a new reporting gRPC client
 func NewClient(ctx context.Context, config ClientConfig) (*client, error) {
+	if config.ResourceVersion == "" {
+		config.ResourceVersion = types.ResourceVersion
+	}
+	if !types.IsSupportedVersion(config.ResourceVersion) {
+		return nil, trace.BadParameter("unsupported resource version %q, supported versions are %v",
+			config.ResourceVersion, types.SupportedVersions)
+	}
+	if config.SigningKey != nil {
+		if err := config.SigningKey.Check(); err != nil {
+			return nil, trace.Wrap(err)
+		}
+	}
+	if err := config.Validation.CheckAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	consent, err := initialConsent(config)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if config.ConsentStatePath == "" && config.PseudonymSecretPath != "" {
+		config.ConsentStatePath = config.PseudonymSecretPath + consentStateSuffix
+	}
+	var optedOut bool
+	if config.ConsentStatePath != "" {
+		state, err := readConsentState(config.ConsentStatePath)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		optedOut = state == consentRevoked
+	}
+	var pseudonyms *pseudonymizer
+	if config.PseudonymSecretPath != "" {
+		pseudonyms, err = LoadPseudonymizer(config.PseudonymSecretPath)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+	}
+	var transparency *transparencyLog
+	if config.TransparencyLog != nil {
+		transparency, err = NewTransparencyLog(*config.TransparencyLog)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+	}
 	conn, err := grpcapi.Dial(config.ServerAddr,
 		grpcapi.WithTransportCredentials(
 			credentials.NewTLS(&tls.Config{
@@ -58,6 +139,9 @@ func NewClient(ctx context.Context, config ClientConfig) (*client, error) {
 				Certificates:       []tls.Certificate{config.Certificate},
 			})))
 	if err != nil {
+		if transparency != nil {
+			transparency.Close()
+		}
 		return nil, trace.Wrap(err)
 	}
 	client := &client{
@@ -65,8 +149,27 @@ func NewClient(ctx context.Context, config ClientConfig) (*client, error) {
 		// give an extra room to the events channel in case events
 		// are generated faster we can flush them (unlikely due to
 		// our events nature)
-		eventsCh: make(chan types.Event, 5*flushCount),
-		ctx:      ctx,
+		eventsCh:         make(chan types.Event, 5*flushCount),
+		ctx:              ctx,
+		serverAddr:       config.ServerAddr,
+		version:          config.ResourceVersion,
+		signingKey:       config.SigningKey,
+		pseudonymizer:    pseudonyms,
+		allowEvents:      eventSet(config.AllowEvents),
+		denyEvents:       eventSet(config.DenyEvents),
+		transparencyLog:  transparency,
+		validation:       config.Validation,
+		consentStatePath: config.ConsentStatePath,
+	}
+	if consent {
+		client.consent = 1
+		if optedOut {
+			client.saveConsentState(consentGranted)
+		}
+	} else if !optedOut && config.ConsentStatePath != "" {
+		// without the saved state the opt-out would be recorded on
+		// every start
+		client.recordOptOut()
 	}
 	go client.receiveAndFlushEvents()
 	return client, nil
@@ -81,11 +184,56 @@ type client struct {
 	events []types.Event
 	// ctx may be used to stop client goroutine
 	ctx context.Context
+	// serverAddr is the address of the reporting server
+	serverAddr string
+	// version is the resource version events are sent in
+	version string
+	// signingKey is the optional key events are signed with
+	signingKey *types.SigningKey
+	// pseudonymizer optionally replaces event identifiers with pseudonyms
+	pseudonymizer *pseudonymizer
+	// consent is 1 if consent to report events has been granted, it is
+	// accessed atomically
+	consent int32
+	// allowEvents is an optional set of names of reported events
+	allowEvents map[string]bool
+	// denyEvents is a set of names of dropped events
+	denyEvents map[string]bool
+	// transparencyLog optionally logs all sent event batches
+	transparencyLog *transparencyLog
+	// validation defines the rules recorded events are validated with
+	validation types.ValidationConfig
+	// consentStatePath is the optional path to the file the reported
+	// consent is saved to
+	consentStatePath string
 }
 
 // Record records an event. Note that the client accumulates events in memory
-// and flushes them every once in a while
+// and flushes them every once in a while. If pseudonymization is enabled,
+// a copy of the event with identifiers replaced is recorded.
 func (c *client) Record(event types.Event) {
+	if !c.isAllowed(event) {
+		log.Debugf("Reporting of %v events is disabled, discarding %v.",
+			event.GetName(), event.GetID())
+		return
+	}
+	c.queue(event)
+}
+
+// queue validates the event and submits it to the events channel
+func (c *client) queue(event types.Event) {
+	if c.pseudonymizer != nil {
+		pseudonymized, err := c.pseudonymizer.Pseudonymize(event)
+		if err != nil {
+			log.Warnf("Discarding event %v that failed to pseudonymize: %v.", event.GetID(), err)
+			return
+		}
+		event = pseudonymized
+	}
+	if err := types.ValidateEvent(event, c.validation); err != nil {
+		log.Warnf("Discarding invalid event %v: %v.", event, err)
+		return
+	}
 	select {
 	case c.eventsCh <- event:
 		log.Debugf("queued %v", event)
@@ -120,6 +268,11 @@ func (c *client) receiveAndFlushEvents() {
 			if err := c.flush(); err != nil {
 				log.Debugf("Failed to flush events: %v.", err)
 			}
+			if c.transparencyLog != nil {
+				if err := c.transparencyLog.Close(); err != nil {
+					log.Warnf("Failed to close transparency log: %v.", err)
+				}
+			}
 			return
 		}
 	}
@@ -127,22 +280,32 @@ func (c *client) receiveAndFlushEvents() {
 
 // flush flushes all accumulated events
 func (c *client) flush() error {
+	if !c.hasConsent() {
+		c.events = withoutConsent(c.events)
+	}
 	if len(c.events) == 0 {
 		return nil // nothing to flush
 	}
-	var grpcEvents reporting.GRPCEvents
-	for _, event := range c.events {
-		grpcEvent, err := types.ToGRPCEvent(event)
-		if err != nil {
-			return trace.Wrap(err)
+	err := c.record()
+	if err != nil && isVersionRejected(err) {
+		if older := olderVersion(c.version); older != "" {
+			log.Debugf("Server does not support resource version %v, falling back to %v.",
+				c.version, older)
+			c.version = older
+			err = c.record()
 		}
-		grpcEvents.Events = append(
-			grpcEvents.Events, grpcEvent)
+	}
+	// the server rejected the batch as invalid so sending it again
+	// would fail the same way
+	if err != nil && trace.IsBadParameter(err) {
+		log.Warnf("Server rejected %v events, discarding them: %v.", len(c.events), err)
+		c.events = []types.Event{}
+		return nil
 	}
 	// if we fail to flush some events here, they will be retried on
 	// the next cycle, we may get duplicates but each event includes
 	// a unique ID which server sinks can use to de-duplicate
-	if _, err := c.client.Record(c.ctx, &grpcEvents); err != nil {
+	if err != nil {
 		return trace.Wrap(err)
 	}
 	log.Debugf("flushed %v events", len(c.events))
@@ -150,6 +313,85 @@ func (c *client) flush() error {
 	return nil
 }
 
+// record sends all accumulated events to the server, events that can't
+// be converted to the current resource version are discarded
+func (c *client) record() error {
+	var grpcEvents reporting.GRPCEvents
+	events := make([]types.Event, 0, len(c.events))
+	for _, event := range c.events {
+		grpcEvent, err := c.toGRPCEvent(event)
+		if err != nil {
+			log.Warnf("Discarding event %v: %v.", event.GetID(), err)
+			continue
+		}
+		events = append(events, event)
+		grpcEvents.Events = append(
+			grpcEvents.Events, grpcEvent)
+	}
+	c.events = events
+	if len(grpcEvents.Events) == 0 {
+		return nil
+	}
+	var trailer metadata.MD
+	if _, err := c.client.Record(c.ctx, &grpcEvents, grpcapi.Trailer(&trailer)); err != nil {
+		err = trail.FromGRPC(err)
+		if supported := trailer.Get(types.SupportedVersionsTrailer); len(supported) != 0 && trace.IsBadParameter(err) {
+			log.Debugf("Server supports resource versions %v.", supported)
+			return trace.Wrap(&types.VersionError{Version: c.version})
+		}
+		return trace.Wrap(err)
+	}
+	if !c.hasConsent() && hasOptOut(events) {
+		c.saveConsentState(consentRevoked)
+	}
+	// the batch has been sent so failing to log it must not fail the flush,
+	// otherwise the batch would be sent again
+	if c.transparencyLog != nil {
+		record := newTransparencyRecord(c.serverAddr, c.version, grpcEvents)
+		if err := c.transparencyLog.Write(record); err != nil {
+			log.Warnf("Failed to write transparency log: %v.", err)
+		}
+	}
+	return nil
+}
+
+// toGRPCEvent converts the event to the current resource version and
+// signs it if the signing key is configured
+func (c *client) toGRPCEvent(event types.Event) (*reporting.GRPCEvent, error) {
+	grpcEvent, err := types.ToGRPCEventVersion(event, c.version)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if c.signingKey != nil {
+		if err := types.SignGRPCEvent(grpcEvent, *c.signingKey); err != nil {
+			return nil, trace.Wrap(err)
+		}
+	}
+	return grpcEvent, nil
+}
+
+// isVersionRejected returns true if the error was returned by a server
+// that does not support the sent resource version
+func isVersionRejected(err error) bool {
+	if types.IsVersionError(err) {
+		return true
+	}
+	// servers that predate versioned resources only accept a single
+	// resource version and reject other versions with this error message
+	return strings.Contains(err.Error(), "expected resource version")
+}
+
+// olderVersion returns the supported version preceding the provided one,
+// or an empty string if there is none
+func olderVersion(version string) string {
+	for i, supported := range types.SupportedVersions {
+		if supported == version && i+1 < len(types.SupportedVersions) {
+			return types.SupportedVersions[i+1]
+		}
+	}
+	return ""
+}
+
 const (
 	// flushInterval is how often the client flushes accumulated events
 	flushInterval = 3 * time.Second
@@ -0,0 +1,167 @@
+/*
+Copyright 2017 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gravitational/reporting/types"
+
+	"github.com/gravitational/trace"
+	log "github.com/sirupsen/logrus"
+)
+
+// SetConsent grants or revokes consent to report events at runtime, when
+// consent is revoked buffered events are discarded and the opt-out is
+// recorded with a single consent event
+func (c *client) SetConsent(granted bool) {
+	var consent int32
+	if granted {
+		consent = 1
+	}
+	previous := atomic.SwapInt32(&c.consent, consent)
+	switch {
+	case previous == 1 && !granted:
+		c.recordOptOut()
+	case previous == 0 && granted:
+		c.saveConsentState(consentGranted)
+	}
+}
+
+// hasConsent returns true if consent to report events has been granted
+func (c *client) hasConsent() bool {
+	return atomic.LoadInt32(&c.consent) == 1
+}
+
+// isAllowed returns true if the event can be reported
+func (c *client) isAllowed(event types.Event) bool {
+	if !c.hasConsent() {
+		return false
+	}
+	name := event.GetName()
+	if len(c.allowEvents) != 0 && !c.allowEvents[name] {
+		return false
+	}
+	return !c.denyEvents[name]
+}
+
+// recordOptOut queues the opt-out consent event
+func (c *client) recordOptOut() {
+	log.Debug("Reporting consent has been revoked.")
+	c.queue(types.NewOptOutEvent())
+}
+
+// saveConsentState saves the reported consent to the consent state file
+// if it is configured
+func (c *client) saveConsentState(state string) {
+	if c.consentStatePath == "" {
+		return
+	}
+	if err := writeConsentState(c.consentStatePath, state); err != nil {
+		log.Warnf("Failed to save reporting consent: %v.", err)
+	}
+}
+
+// readConsentState returns the consent saved to the provided file, or an
+// empty string if it has not been saved
+func readConsentState(path string) (string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", trace.ConvertSystemError(err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// writeConsentState replaces the consent saved to the provided file
+func writeConsentState(path, state string) error {
+	tmpPath, err := writeTempFile(path, []byte(state))
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return trace.ConvertSystemError(err)
+	}
+	return nil
+}
+
+// hasOptOut returns true if the events include the opt-out consent event
+func hasOptOut(events []types.Event) bool {
+	for _, event := range events {
+		if event.GetName() == types.EventTypeConsent {
+			return true
+		}
+	}
+	return false
+}
+
+// withoutConsent returns events that can be sent without consent
+func withoutConsent(events []types.Event) []types.Event {
+	var result []types.Event
+	for _, event := range events {
+		if event.GetName() == types.EventTypeConsent {
+			result = append(result, event)
+		}
+	}
+	return result
+}
+
+// initialConsent returns whether reporting is initially enabled, the
+// environment variable takes precedence over the config
+func initialConsent(config ClientConfig) (bool, error) {
+	value := os.Getenv(DisableEnvVar)
+	if value == "" {
+		return !config.Disabled, nil
+	}
+	disabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, trace.BadParameter("invalid %v value %q, expected a boolean",
+			DisableEnvVar, value)
+	}
+	return !disabled, nil
+}
+
+// eventSet returns a set of the provided event names
+func eventSet(names []string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[name] = true
+	}
+	return set
+}
+
+const (
+	// DisableEnvVar is the environment variable that disables reporting
+	// when set to true and enables it when set to false, overriding
+	// the client config
+	DisableEnvVar = "REPORTING_DISABLED"
+	// consentStateSuffix is the suffix of the consent state file saved
+	// next to the pseudonym secret
+	consentStateSuffix = ".consent"
+	// consentGranted is the saved state of granted consent
+	consentGranted = "granted"
+	// consentRevoked is the saved state of revoked consent, it is saved
+	// once the opt-out has been sent
+	consentRevoked = "revoked"
+)
@@ -0,0 +1,158 @@
+/*
+Copyright 2017 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gravitational/reporting/types"
+
+	"github.com/gravitational/trace"
+	log "github.com/sirupsen/logrus"
+)
+
+// HeartbeatFunc fetches the latest heartbeat from the control plane
+type HeartbeatFunc func(context.Context) (*types.Heartbeat, error)
+
+// HeartbeatConfig defines the heartbeat watcher config
+type HeartbeatConfig struct {
+	// Fetch is called to fetch each heartbeat
+	Fetch HeartbeatFunc
+	// OnHeartbeat is an optional callback called with each fetched heartbeat
+	OnHeartbeat func(*types.Heartbeat)
+	// Interval is how often heartbeats are fetched unless the last
+	// heartbeat recommends another interval
+	Interval time.Duration
+	// MinInterval and MaxInterval bound intervals recommended by heartbeats
+	MinInterval time.Duration
+	MaxInterval time.Duration
+}
+
+// CheckAndSetDefaults validates the config and sets default values
+func (c *HeartbeatConfig) CheckAndSetDefaults() error {
+	if c.Fetch == nil {
+		return trace.BadParameter("missing Fetch")
+	}
+	if c.Interval < 0 || c.MinInterval < 0 || c.MaxInterval < 0 {
+		return trace.BadParameter("intervals can't be negative")
+	}
+	if c.Interval == 0 {
+		c.Interval = heartbeatInterval
+	}
+	if c.MinInterval == 0 {
+		c.MinInterval = heartbeatMinInterval
+	}
+	if c.MaxInterval == 0 {
+		c.MaxInterval = heartbeatMaxInterval
+	}
+	if c.MinInterval > c.MaxInterval {
+		return trace.BadParameter("min interval %v is greater than max interval %v",
+			c.MinInterval, c.MaxInterval)
+	}
+	return nil
+}
+
+// WatchHeartbeats fetches a heartbeat right away and then periodically
+// until the provided context is canceled, the returned watcher gives
+// access to the latest heartbeat
+func WatchHeartbeats(ctx context.Context, config HeartbeatConfig) (*heartbeatWatcher, error) {
+	if err := config.CheckAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	watcher := &heartbeatWatcher{
+		HeartbeatConfig: config,
+	}
+	go watcher.fetchHeartbeats(ctx)
+	return watcher, nil
+}
+
+type heartbeatWatcher struct {
+	HeartbeatConfig
+	sync.RWMutex
+	// heartbeat is the latest fetched heartbeat
+	heartbeat *types.Heartbeat
+}
+
+// Heartbeat returns the latest fetched heartbeat or nil if no heartbeat
+// has been fetched yet
+func (w *heartbeatWatcher) Heartbeat() *types.Heartbeat {
+	w.RLock()
+	defer w.RUnlock()
+	return w.heartbeat
+}
+
+// HasFeature returns true if the feature flag is enabled by the latest
+// fetched heartbeat
+func (w *heartbeatWatcher) HasFeature(name string) bool {
+	heartbeat := w.Heartbeat()
+	return heartbeat != nil && heartbeat.HasFeature(name)
+}
+
+// fetchHeartbeats fetches heartbeats with the interval recommended by
+// the latest heartbeat
+func (w *heartbeatWatcher) fetchHeartbeats(ctx context.Context) {
+	for {
+		heartbeat, err := w.Fetch(ctx)
+		if err != nil {
+			log.Warnf("Failed to fetch heartbeat: %v.", trace.DebugReport(err))
+		} else if heartbeat != nil {
+			w.Lock()
+			w.heartbeat = heartbeat
+			w.Unlock()
+			if w.OnHeartbeat != nil {
+				w.OnHeartbeat(heartbeat)
+			}
+		}
+		timer := time.NewTimer(w.interval())
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			log.Debug("Heartbeat watcher is shutting down.")
+			return
+		}
+	}
+}
+
+// interval returns the interval until the next heartbeat is fetched
+func (w *heartbeatWatcher) interval() time.Duration {
+	heartbeat := w.Heartbeat()
+	if heartbeat == nil || heartbeat.Spec.PollInterval == 0 {
+		return w.Interval
+	}
+	interval := heartbeat.Spec.PollInterval
+	if interval < w.MinInterval {
+		return w.MinInterval
+	}
+	if interval > w.MaxInterval {
+		return w.MaxInterval
+	}
+	return interval
+}
+
+const (
+	// heartbeatInterval is how often heartbeats are fetched by default
+	heartbeatInterval = time.Hour
+	// heartbeatMinInterval is the default shortest interval a heartbeat
+	// can recommend
+	heartbeatMinInterval = time.Minute
+	// heartbeatMaxInterval is the default longest interval a heartbeat
+	// can recommend
+	heartbeatMaxInterval = 24 * time.Hour
+)
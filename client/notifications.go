@@ -0,0 +1,97 @@
+/*
+Copyright 2017 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gravitational/reporting/types"
+
+	"github.com/gravitational/trace"
+)
+
+// Dismissals keeps track of notifications dismissed by users
+type Dismissals interface {
+	// Dismiss marks the notification as dismissed
+	Dismiss(types.Notification) error
+	// IsDismissed returns true if notification with the provided ID
+	// has been dismissed
+	IsDismissed(id string) bool
+}
+
+// NewDismissals returns dismissals kept in memory
+func NewDismissals() *memoryDismissals {
+	return &memoryDismissals{
+		ids: make(map[string]bool),
+	}
+}
+
+type memoryDismissals struct {
+	sync.RWMutex
+	// ids is a set of dismissed notification IDs
+	ids map[string]bool
+}
+
+// Dismiss marks the notification as dismissed
+func (d *memoryDismissals) Dismiss(n types.Notification) error {
+	if !n.Dismissible {
+		return trace.BadParameter("notification %q is not dismissible", n.ID)
+	}
+	if n.ID == "" {
+		return trace.BadParameter("missing notification ID")
+	}
+	d.Lock()
+	defer d.Unlock()
+	d.ids[n.ID] = true
+	return nil
+}
+
+// IsDismissed returns true if notification with the provided ID has been
+// dismissed
+func (d *memoryDismissals) IsDismissed(id string) bool {
+	d.RLock()
+	defer d.RUnlock()
+	return d.ids[id]
+}
+
+// ActiveNotifications returns notifications of the heartbeat that are
+// active at the provided time and have not been dismissed, notifications
+// with the same ID are returned once
+func ActiveNotifications(heartbeat *types.Heartbeat, now time.Time, dismissals Dismissals) []types.Notification {
+	if heartbeat == nil {
+		return nil
+	}
+	var active []types.Notification
+	seen := make(map[string]bool)
+	for _, n := range heartbeat.Spec.Notifications {
+		if !n.IsActive(now) {
+			continue
+		}
+		if n.ID != "" {
+			if seen[n.ID] {
+				continue
+			}
+			seen[n.ID] = true
+			if n.Dismissible && dismissals != nil && dismissals.IsDismissed(n.ID) {
+				continue
+			}
+		}
+		active = append(active, n)
+	}
+	return active
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2017 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/gravitational/reporting/types"
+
+	"github.com/gravitational/trace"
+)
+
+// NewPseudonymizer returns a pseudonymizer that replaces identifiers with
+// their HMAC keyed with the provided installation secret
+func NewPseudonymizer(secret []byte) (*pseudonymizer, error) {
+	if len(secret) < pseudonymSecretSize {
+		return nil, trace.BadParameter("pseudonym secret must be at least %v bytes",
+			pseudonymSecretSize)
+	}
+	return &pseudonymizer{
+		secret: secret,
+	}, nil
+}
+
+// LoadPseudonymizer returns a pseudonymizer with the installation secret
+// read from the provided file, the secret is generated and saved to the
+// file if it does not exist, so pseudonyms are consistent across restarts
+func LoadPseudonymizer(path string) (*pseudonymizer, error) {
+	secret, err := readSecret(path)
+	if err != nil && !trace.IsNotFound(err) {
+		return nil, trace.Wrap(err)
+	}
+	if trace.IsNotFound(err) {
+		secret, err = generateSecret(path)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+	}
+	return NewPseudonymizer(secret)
+}
+
+type pseudonymizer struct {
+	// secret is the installation secret pseudonyms are keyed with
+	secret []byte
+}
+
+// Pseudonym returns the pseudonym of the provided identifier, empty
+// identifiers are returned as is
+func (p *pseudonymizer) Pseudonym(id string) string {
+	if id == "" {
+		return id
+	}
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write([]byte(id))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Pseudonymize returns a copy of the event with identifier fields replaced
+// with pseudonyms, the provided event is not modified
+func (p *pseudonymizer) Pseudonymize(event types.Event) (types.Event, error) {
+	if _, ok := event.(types.IdentifierMapper); !ok {
+		return event, nil
+	}
+	clone, err := copyEvent(event)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	clone.(types.IdentifierMapper).MapIdentifiers(p.Pseudonym)
+	return clone, nil
+}
+
+// copyEvent returns a deep copy of the event made by encoding it to JSON
+// and decoding it into a new value of the same type
+func copyEvent(event types.Event) (types.Event, error) {
+	value := reflect.ValueOf(event)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return nil, trace.BadParameter("expected event pointer, got %T", event)
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	clone := reflect.New(value.Elem().Type()).Interface()
+	if err := json.Unmarshal(data, clone); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return clone.(types.Event), nil
+}
+
+// readSecret reads the hex-encoded secret from the provided file
+func readSecret(path string) ([]byte, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, trace.ConvertSystemError(err)
+	}
+	secret, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, trace.BadParameter("malformed pseudonym secret in %v", path)
+	}
+	return secret, nil
+}
+
+// generateSecret generates a new secret and saves it hex-encoded to the
+// provided file, if the file has been created concurrently the secret
+// is read from it instead. The secret is written to a temporary file
+// that is linked in place once complete, so the file is never observed
+// partially written and an existing file is never replaced
+func generateSecret(path string) ([]byte, error) {
+	secret := make([]byte, pseudonymSecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	tmpPath, err := writeTempFile(path, []byte(hex.EncodeToString(secret)))
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	defer os.Remove(tmpPath)
+	if err := os.Link(tmpPath, path); err != nil {
+		if os.IsExist(err) {
+			return readSecret(path)
+		}
+		return nil, trace.ConvertSystemError(err)
+	}
+	return secret, nil
+}
+
+// writeTempFile writes the data to a new temporary file in the directory
+// of the provided path and returns the temporary file path
+func writeTempFile(path string, data []byte) (string, error) {
+	f, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return "", trace.ConvertSystemError(err)
+	}
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", trace.ConvertSystemError(err)
+	}
+	return f.Name(), nil
+}
+
+const (
+	// pseudonymSecretSize is the size of generated installation secrets
+	pseudonymSecretSize = 32
+)
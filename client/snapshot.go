@@ -0,0 +1,92 @@
+/*
+Copyright 2017 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/gravitational/reporting/types"
+
+	"github.com/gravitational/trace"
+	log "github.com/sirupsen/logrus"
+)
+
+// SnapshotFunc gathers the current state of the installation
+type SnapshotFunc func(context.Context) (*types.SnapshotEvent, error)
+
+// SnapshotConfig defines the snapshot reporter config
+type SnapshotConfig struct {
+	// Client is the client snapshots are recorded with
+	Client Client
+	// Gather is called to gather each snapshot
+	Gather SnapshotFunc
+	// Interval is how often snapshots are gathered
+	Interval time.Duration
+}
+
+// CheckAndSetDefaults validates the config and sets default values
+func (c *SnapshotConfig) CheckAndSetDefaults() error {
+	if c.Client == nil {
+		return trace.BadParameter("missing Client")
+	}
+	if c.Gather == nil {
+		return trace.BadParameter("missing Gather")
+	}
+	if c.Interval < 0 {
+		return trace.BadParameter("interval can't be negative")
+	}
+	if c.Interval == 0 {
+		c.Interval = snapshotInterval
+	}
+	return nil
+}
+
+// StartSnapshots gathers and records a snapshot right away and then
+// periodically until the provided context is canceled
+func StartSnapshots(ctx context.Context, config SnapshotConfig) error {
+	if err := config.CheckAndSetDefaults(); err != nil {
+		return trace.Wrap(err)
+	}
+	go recordSnapshots(ctx, config)
+	return nil
+}
+
+// recordSnapshots gathers and records snapshots on the configured schedule
+func recordSnapshots(ctx context.Context, config SnapshotConfig) {
+	ticker := time.NewTicker(config.Interval)
+	defer ticker.Stop()
+	for {
+		snapshot, err := config.Gather(ctx)
+		if err != nil {
+			log.Warnf("Failed to gather snapshot: %v.", trace.DebugReport(err))
+		} else if snapshot != nil {
+			config.Client.Record(snapshot)
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			log.Debug("Snapshot reporter is shutting down.")
+			return
+		}
+	}
+}
+
+const (
+	// snapshotInterval is how often snapshots are gathered by default
+	snapshotInterval = time.Hour
+)
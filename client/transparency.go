@@ -0,0 +1,262 @@
+/*
+Copyright 2017 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gravitational/reporting"
+
+	"github.com/gravitational/trace"
+)
+
+// TransparencyLogConfig is the transparency log configuration
+type TransparencyLogConfig struct {
+	// Path is the path to the log file, rotated files get a numeric
+	// suffix, e.g. "<path>.1" is the most recently rotated file
+	Path string
+	// MaxSize is the size in bytes the log file is rotated at
+	MaxSize int64
+	// MaxBackups is the number of rotated files to keep
+	MaxBackups int
+}
+
+// CheckAndSetDefaults validates the config and sets default values
+func (c *TransparencyLogConfig) CheckAndSetDefaults() error {
+	if c.Path == "" {
+		return trace.BadParameter("missing transparency log path")
+	}
+	if c.MaxSize < 0 {
+		return trace.BadParameter("transparency log max size can't be negative")
+	}
+	if c.MaxBackups < 0 {
+		return trace.BadParameter("transparency log max backups can't be negative")
+	}
+	if c.MaxSize == 0 {
+		c.MaxSize = transparencyLogMaxSize
+	}
+	if c.MaxBackups == 0 {
+		c.MaxBackups = transparencyLogMaxBackups
+	}
+	return nil
+}
+
+// TransparencyRecord is a transparency log entry, it describes a single
+// batch of events sent to the server
+type TransparencyRecord struct {
+	// Time is the time the batch was sent
+	Time time.Time `json:"time"`
+	// Server is the address of the server the batch was sent to
+	Server string `json:"server"`
+	// Version is the resource version events were sent in
+	Version string `json:"version"`
+	// Events are the event payloads exactly as they were sent
+	Events []json.RawMessage `json:"events"`
+}
+
+// NewTransparencyLog returns a new append-only log of sent event batches
+// that is rotated once it grows over the configured size
+func NewTransparencyLog(config TransparencyLogConfig) (*transparencyLog, error) {
+	if err := config.CheckAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	l := &transparencyLog{
+		TransparencyLogConfig: config,
+	}
+	if err := l.open(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return l, nil
+}
+
+type transparencyLog struct {
+	TransparencyLogConfig
+	sync.Mutex
+	// file is the current log file
+	file *os.File
+	// size is the size of the current log file
+	size int64
+}
+
+// Write appends the record to the log, rotating the log file first if
+// the record does not fit into it
+func (l *transparencyLog) Write(record TransparencyRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	data = append(data, '\n')
+	l.Lock()
+	defer l.Unlock()
+	if l.file == nil {
+		return trace.BadParameter("transparency log is closed")
+	}
+	if l.size > 0 && l.size+int64(len(data)) > l.MaxSize {
+		if err := l.rotate(); err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	n, err := l.file.Write(data)
+	l.size += int64(n)
+	if err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	return nil
+}
+
+// Close closes the log file
+func (l *transparencyLog) Close() error {
+	l.Lock()
+	defer l.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return trace.ConvertSystemError(err)
+}
+
+// open opens the current log file for appending
+func (l *transparencyLog) open() error {
+	file, err := os.OpenFile(l.Path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return trace.ConvertSystemError(err)
+	}
+	l.file = file
+	l.size = info.Size()
+	return nil
+}
+
+// rotate moves the current file in place of the most recent rotated file
+// and opens a new one, the log file is reopened even if rotation fails so
+// that the log keeps being written
+func (l *transparencyLog) rotate() error {
+	if err := l.file.Close(); err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	l.file = nil
+	err := l.shift()
+	if openErr := l.open(); openErr != nil {
+		return trace.NewAggregate(err, openErr)
+	}
+	return trace.Wrap(err)
+}
+
+// shift shifts rotated files by one dropping the oldest one and moves
+// the current file in place of the most recent rotated file
+func (l *transparencyLog) shift() error {
+	for i := l.MaxBackups - 1; i > 0; i-- {
+		err := os.Rename(rotatedPath(l.Path, i), rotatedPath(l.Path, i+1))
+		if err != nil && !os.IsNotExist(err) {
+			return trace.ConvertSystemError(err)
+		}
+	}
+	if err := os.Rename(l.Path, rotatedPath(l.Path, 1)); err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	return nil
+}
+
+// ReadTransparencyLog returns records from the transparency log at the
+// provided path including rotated files, oldest records first
+func ReadTransparencyLog(path string) ([]TransparencyRecord, error) {
+	var paths []string
+	for i := 1; ; i++ {
+		if _, err := os.Stat(rotatedPath(path, i)); err != nil {
+			if os.IsNotExist(err) {
+				break
+			}
+			return nil, trace.ConvertSystemError(err)
+		}
+		paths = append([]string{rotatedPath(path, i)}, paths...)
+	}
+	paths = append(paths, path)
+	var records []TransparencyRecord
+	for _, p := range paths {
+		fileRecords, err := readTransparencyLogFile(p)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		records = append(records, fileRecords...)
+	}
+	return records, nil
+}
+
+// readTransparencyLogFile returns records from a single log file
+func readTransparencyLogFile(path string) ([]TransparencyRecord, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, trace.ConvertSystemError(err)
+	}
+	defer file.Close()
+	var records []TransparencyRecord
+	reader := bufio.NewReader(file)
+	for line := 1; ; line++ {
+		data, err := reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, trace.ConvertSystemError(err)
+		}
+		// a partially written last line is left by an interrupted write
+		if len(bytes.TrimSpace(data)) != 0 && (err == nil || json.Valid(data)) {
+			var record TransparencyRecord
+			if err := json.Unmarshal(data, &record); err != nil {
+				return nil, trace.BadParameter("malformed record at %v:%v: %v", path, line, err)
+			}
+			records = append(records, record)
+		}
+		if err == io.EOF {
+			return records, nil
+		}
+	}
+}
+
+// newTransparencyRecord returns a record describing the sent events
+func newTransparencyRecord(server, version string, events reporting.GRPCEvents) TransparencyRecord {
+	record := TransparencyRecord{
+		Time:    time.Now().UTC(),
+		Server:  server,
+		Version: version,
+	}
+	for _, event := range events.Events {
+		record.Events = append(record.Events, json.RawMessage(event.Data))
+	}
+	return record
+}
+
+// rotatedPath returns the path of the rotated log file with the provided index
+func rotatedPath(path string, index int) string {
+	return fmt.Sprintf("%v.%v", path, index)
+}
+
+const (
+	// transparencyLogMaxSize is the default size the log is rotated at
+	transparencyLogMaxSize = 10 * 1024 * 1024
+	// transparencyLogMaxBackups is the default number of rotated files to keep
+	transparencyLogMaxBackups = 5
+)
@@ -0,0 +1,138 @@
+/*
+Copyright 2017 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command reporting-log prints the transparency log written by the
+// reporting client so users can audit what was sent to the server
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gravitational/reporting/client"
+
+	"github.com/gravitational/trace"
+)
+
+func main() {
+	summary := flag.Bool("summary", false, "only print the summary of sent events")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %v [-summary] <transparency log path>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(os.Stdout, flag.Arg(0), *summary); err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %v\n", trace.UserMessage(err))
+		os.Exit(1)
+	}
+}
+
+// run prints records of the transparency log at the provided path
+// followed by their summary
+func run(w io.Writer, path string, summaryOnly bool) error {
+	records, err := client.ReadTransparencyLog(path)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if !summaryOnly {
+		for _, record := range records {
+			if err := printRecord(w, record); err != nil {
+				return trace.Wrap(err)
+			}
+		}
+	}
+	return trace.Wrap(printSummary(w, records))
+}
+
+// printRecord prints the batch header followed by indented event payloads
+func printRecord(w io.Writer, record client.TransparencyRecord) error {
+	fmt.Fprintf(w, "%v sent %v event(s) to %v in version %v\n",
+		record.Time.Format(time.RFC3339), len(record.Events), record.Server, record.Version)
+	for _, event := range record.Events {
+		var out bytes.Buffer
+		if err := json.Indent(&out, event, "  ", "  "); err != nil {
+			return trace.Wrap(err)
+		}
+		fmt.Fprintf(w, "  %s\n", out.Bytes())
+	}
+	fmt.Fprintln(w)
+	return nil
+}
+
+// printSummary prints the number of sent batches and events per server
+// and event name
+func printSummary(w io.Writer, records []client.TransparencyRecord) error {
+	if len(records) == 0 {
+		fmt.Fprintln(w, "No events have been sent.")
+		return nil
+	}
+	servers := make(map[string]int)
+	names := make(map[string]int)
+	var total int
+	for _, record := range records {
+		servers[record.Server] += len(record.Events)
+		for _, event := range record.Events {
+			var header eventHeader
+			if err := json.Unmarshal(event, &header); err != nil {
+				return trace.Wrap(err)
+			}
+			names[header.Metadata.Name]++
+			total++
+		}
+	}
+	fmt.Fprintf(w, "Sent %v event(s) in %v batch(es) between %v and %v.\n\n", total, len(records),
+		records[0].Time.Format(time.RFC3339), records[len(records)-1].Time.Format(time.RFC3339))
+	if err := printCounts(w, "Server", "Events", servers); err != nil {
+		return trace.Wrap(err)
+	}
+	fmt.Fprintln(w)
+	return trace.Wrap(printCounts(w, "Event", "Count", names))
+}
+
+// printCounts prints a table of counts sorted by key
+func printCounts(w io.Writer, keyHeader, countHeader string, counts map[string]int) error {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "%v\t%v\n", keyHeader, countHeader)
+	for _, key := range keys {
+		fmt.Fprintf(tw, "%v\t%v\n", key, counts[key])
+	}
+	return trace.Wrap(tw.Flush())
+}
+
+// eventHeader is the part of the event payload used in the summary
+type eventHeader struct {
+	// Metadata is the event metadata
+	Metadata struct {
+		// Name is the event name
+		Name string `json:"name"`
+	} `json:"metadata"`
+}
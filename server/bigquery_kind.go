@@ -0,0 +1,335 @@
+/*
+Copyright 2017 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gravitational/reporting/types"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/gravitational/trace"
+	log "github.com/sirupsen/logrus"
+)
+
+// kindTable returns the name and schema of the table for the provided
+// event's kind, creating the table or adding missing columns to it when
+// the table is used for the first time
+func (q *bigQuerySink) kindTable(event types.Event) (string, bigquery.Schema, error) {
+	q.tablesMu.Lock()
+	defer q.tablesMu.Unlock()
+	name := kindTableName(event.GetName())
+	if schema, ok := q.tables[event.GetName()]; ok {
+		return name, schema, nil
+	}
+	spec, err := eventSpec(event)
+	if err != nil {
+		return "", nil, trace.Wrap(err)
+	}
+	schema, err := kindTableSchema(spec.Type())
+	if err != nil {
+		return "", nil, trace.Wrap(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), bqUploadTimeout)
+	defer cancel()
+	table := q.client.Dataset(bqDatasetName).Table(name)
+	err = table.Create(ctx, &bigquery.TableMetadata{Schema: schema})
+	if err != nil {
+		if !strings.Contains(err.Error(), "Already Exists") {
+			return "", nil, trace.Wrap(err)
+		}
+		log.Debugf("table %q already exists", name)
+		if err := updateTableSchema(ctx, table, schema); err != nil {
+			return "", nil, trace.Wrap(err)
+		}
+	}
+	if event.GetName() == types.EventTypeSnapshot {
+		if err := q.createSnapshotView(ctx, name, ""); err != nil {
+			return "", nil, trace.Wrap(err)
+		}
+	}
+	q.tables[event.GetName()] = schema
+	return name, schema, nil
+}
+
+// updateTableSchema adds columns present in the provided schema but missing
+// from the existing table, e.g. after a new field was added to an event spec
+func updateTableSchema(ctx context.Context, table *bigquery.Table, schema bigquery.Schema) error {
+	metadata, err := table.Metadata(ctx)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	existing := make(map[string]bool)
+	for _, field := range metadata.Schema {
+		existing[strings.ToLower(field.Name)] = true
+	}
+	updated := metadata.Schema
+	for _, field := range schema {
+		if !existing[strings.ToLower(field.Name)] {
+			updated = append(updated, field)
+		}
+	}
+	if len(updated) == len(metadata.Schema) {
+		return nil
+	}
+	_, err = table.Update(ctx, bigquery.TableMetadataToUpdate{Schema: updated}, metadata.ETag)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	log.Infof("added %v columns to table %q", len(updated)-len(metadata.Schema), table.TableID)
+	return nil
+}
+
+// kindTableName returns the name of the table for the provided event name
+func kindTableName(eventName string) string {
+	return bqTableName + "_" + invalidTableChars.ReplaceAllString(eventName, "_")
+}
+
+// kindTableSchema returns schema of a per-kind table for the provided event
+// spec type: the event timestamp and signature status followed by all spec
+// fields
+func kindTableSchema(specType reflect.Type) (bigquery.Schema, error) {
+	fields, err := structSchema(specType)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	schema := bigquery.Schema{{
+		Name:     bqTimeColumn,
+		Required: true,
+		Type:     bigquery.TimestampFieldType,
+	}, {
+		Name: bqSignatureColumn,
+		Type: bigquery.StringFieldType,
+	}}
+	return append(schema, fields...), nil
+}
+
+// structSchema returns BigQuery schema of the provided struct type, with
+// columns named after fields' JSON names
+func structSchema(structType reflect.Type) (bigquery.Schema, error) {
+	var schema bigquery.Schema
+	for _, field := range jsonFields(structType) {
+		fieldSchema, err := fieldTypeSchema(field.Type)
+		if err != nil {
+			return nil, trace.Wrap(err, "field %v.%v", structType.Name(), field.Name)
+		}
+		fieldSchema.Name = field.name
+		schema = append(schema, fieldSchema)
+	}
+	return schema, nil
+}
+
+// fieldTypeSchema returns BigQuery schema of a column of the provided type
+func fieldTypeSchema(fieldType reflect.Type) (*bigquery.FieldSchema, error) {
+	if fieldType == timeType {
+		return &bigquery.FieldSchema{Type: bigquery.TimestampFieldType}, nil
+	}
+	switch fieldType.Kind() {
+	case reflect.String:
+		return &bigquery.FieldSchema{Type: bigquery.StringFieldType}, nil
+	case reflect.Bool:
+		return &bigquery.FieldSchema{Type: bigquery.BooleanFieldType}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &bigquery.FieldSchema{Type: bigquery.IntegerFieldType}, nil
+	case reflect.Float32, reflect.Float64:
+		return &bigquery.FieldSchema{Type: bigquery.FloatFieldType}, nil
+	case reflect.Ptr:
+		return fieldTypeSchema(fieldType.Elem())
+	case reflect.Struct:
+		schema, err := structSchema(fieldType)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		return &bigquery.FieldSchema{Type: bigquery.RecordFieldType, Schema: schema}, nil
+	case reflect.Slice, reflect.Array:
+		if fieldType.Elem().Kind() == reflect.Uint8 {
+			return &bigquery.FieldSchema{Type: bigquery.BytesFieldType}, nil
+		}
+		elem, err := fieldTypeSchema(fieldType.Elem())
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		if elem.Repeated {
+			return nil, trace.BadParameter("nested repeated fields are not supported")
+		}
+		elem.Repeated = true
+		return elem, nil
+	case reflect.Map:
+		// maps are represented as repeated key/value records
+		if fieldType.Key().Kind() != reflect.String {
+			return nil, trace.BadParameter("only maps with string keys are supported")
+		}
+		value, err := fieldTypeSchema(fieldType.Elem())
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		value.Name = bqMapValueColumn
+		return &bigquery.FieldSchema{
+			Type:     bigquery.RecordFieldType,
+			Repeated: true,
+			Schema: bigquery.Schema{
+				{Name: bqMapKeyColumn, Type: bigquery.StringFieldType, Required: true},
+				value,
+			},
+		}, nil
+	}
+	return nil, trace.BadParameter("unsupported field type %v", fieldType)
+}
+
+// eventToRow converts a single event to a row of its per-kind table
+func eventToRow(event types.Event) (*bqRow, error) {
+	spec, err := eventSpec(event)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	values := structValues(spec)
+	values[bqTimeColumn] = event.GetMetadata().Created
+	values[bqSignatureColumn] = signatureStatus(event)
+	row := &bqRow{values: values}
+	if id, ok := values[bqIDColumn].(string); ok {
+		row.insertID = id
+	}
+	return row, nil
+}
+
+// structValues returns values of the provided struct's fields keyed by
+// their JSON names
+func structValues(value reflect.Value) map[string]bigquery.Value {
+	values := make(map[string]bigquery.Value)
+	for _, field := range jsonFields(value.Type()) {
+		values[field.name] = fieldValue(value.FieldByIndex(field.Index))
+	}
+	return values
+}
+
+// fieldValue converts the provided field value to the representation
+// matching its column schema
+func fieldValue(value reflect.Value) bigquery.Value {
+	if value.Type() == timeType {
+		return value.Interface()
+	}
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+			return nil
+		}
+		return fieldValue(value.Elem())
+	case reflect.Struct:
+		return structValues(value)
+	case reflect.Slice, reflect.Array:
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			return value.Interface()
+		}
+		values := make([]bigquery.Value, value.Len())
+		for i := 0; i < value.Len(); i++ {
+			values[i] = fieldValue(value.Index(i))
+		}
+		return values
+	case reflect.Map:
+		keys := value.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		values := make([]bigquery.Value, len(keys))
+		for i, key := range keys {
+			values[i] = map[string]bigquery.Value{
+				bqMapKeyColumn:   key.String(),
+				bqMapValueColumn: fieldValue(value.MapIndex(key)),
+			}
+		}
+		return values
+	}
+	return value.Interface()
+}
+
+// eventSpec returns the spec struct of the provided event
+func eventSpec(event types.Event) (reflect.Value, error) {
+	value := reflect.Indirect(reflect.ValueOf(event))
+	if value.Kind() != reflect.Struct {
+		return reflect.Value{}, trace.BadParameter("unsupported event type %T", event)
+	}
+	spec := value.FieldByName("Spec")
+	if !spec.IsValid() || spec.Kind() != reflect.Struct {
+		return reflect.Value{}, trace.BadParameter("event type %T has no spec", event)
+	}
+	return spec, nil
+}
+
+// jsonField is a struct field along with its JSON name
+type jsonField struct {
+	reflect.StructField
+	// name is the field JSON name
+	name string
+}
+
+// jsonFields returns exported fields of the provided struct type that are
+// included in its JSON representation
+func jsonFields(structType reflect.Type) []jsonField {
+	var fields []jsonField
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if field.PkgPath != "" {
+			continue // unexported
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields = append(fields, jsonField{StructField: field, name: name})
+	}
+	return fields
+}
+
+// bqRow is a single row of a per-kind table
+type bqRow struct {
+	// insertID is the row ID used for de-duplication
+	insertID string
+	// values are the row column values
+	values map[string]bigquery.Value
+}
+
+// Save returns the row values and insert ID
+func (r *bqRow) Save() (map[string]bigquery.Value, string, error) {
+	return r.values, r.insertID, nil
+}
+
+var (
+	// timeType is the type of timestamp fields
+	timeType = reflect.TypeOf(time.Time{})
+	// invalidTableChars matches characters not allowed in table names
+	invalidTableChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+)
+
+const (
+	// bqTimeColumn is the name of the event timestamp column
+	bqTimeColumn = "time"
+	// bqSignatureColumn is the name of the event signature status column
+	bqSignatureColumn = "signature"
+	// bqIDColumn is the name of the event ID column of per-kind tables
+	bqIDColumn = "id"
+	// bqMapKeyColumn is the name of the key column of map records
+	bqMapKeyColumn = "key"
+	// bqMapValueColumn is the name of the value column of map records
+	bqMapValueColumn = "value"
+)
@@ -0,0 +1,174 @@
+/*
+Copyright 2017 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"time"
+
+	"github.com/gravitational/reporting/types"
+
+	"github.com/gravitational/trace"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// KafkaConfig is config for Kafka sink
+type KafkaConfig struct {
+	// Brokers is the list of seed broker addresses
+	Brokers []string `json:"brokers"`
+	// Topic is the topic events are published to unless their kind has
+	// a topic in KindTopics
+	Topic string `json:"topic"`
+	// KindTopics maps event names, such as "user", to topics
+	KindTopics map[string]string `json:"kindTopics,omitempty"`
+	// Timeout is how long to wait for a batch of events to be acknowledged
+	Timeout time.Duration `json:"timeout,omitempty"`
+	// TLS is an optional TLS config to connect to brokers with
+	TLS *tls.Config `json:"-"`
+}
+
+// CheckAndSetDefaults makes sure that Kafka sink config is valid and sets
+// defaults for the unspecified fields
+func (c *KafkaConfig) CheckAndSetDefaults() error {
+	if len(c.Brokers) == 0 {
+		return trace.BadParameter("kafka config is missing brokers")
+	}
+	if c.Topic == "" {
+		return trace.BadParameter("kafka config is missing topic")
+	}
+	for name, topic := range c.KindTopics {
+		if topic == "" {
+			return trace.BadParameter("kafka config is missing topic for %q events", name)
+		}
+	}
+	if c.Timeout == 0 {
+		c.Timeout = kafkaTimeout
+	}
+	return nil
+}
+
+// kafkaProducer is the part of the Kafka client used by the sink, it may be
+// substituted with an in-process stand-in in tests
+type kafkaProducer interface {
+	// ProduceSync publishes records and waits until they are acknowledged
+	ProduceSync(ctx context.Context, records ...*kgo.Record) kgo.ProduceResults
+	// Close flushes and closes the producer
+	Close()
+}
+
+// NewKafkaSink returns a new sink that publishes events to Kafka
+func NewKafkaSink(config KafkaConfig) (*kafkaSink, error) {
+	if err := config.CheckAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	options := []kgo.Opt{
+		kgo.SeedBrokers(config.Brokers...),
+		// wait for all in-sync replicas, which is also required by the
+		// idempotent producer that is enabled by default: the brokers
+		// de-duplicate records retried by the client and keep their order
+		kgo.RequiredAcks(kgo.AllISRAcks()),
+		kgo.ProduceRequestTimeout(config.Timeout),
+	}
+	if config.TLS != nil {
+		options = append(options, kgo.DialTLSConfig(config.TLS))
+	}
+	client, err := kgo.NewClient(options...)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return newKafkaSink(config, client), nil
+}
+
+// newKafkaSink returns a new Kafka sink that uses the provided producer
+func newKafkaSink(config KafkaConfig, producer kafkaProducer) *kafkaSink {
+	return &kafkaSink{
+		KafkaConfig: config,
+		producer:    producer,
+	}
+}
+
+type kafkaSink struct {
+	KafkaConfig
+	producer kafkaProducer
+}
+
+// Put publishes each event as a record keyed by the event account ID, so
+// the default partitioner sends all events of an account to the same
+// partition preserving their order. Returns an error if any of the records
+// has not been acknowledged
+func (s *kafkaSink) Put(events []types.Event) error {
+	var records []*kgo.Record
+	for _, event := range events {
+		record, err := s.eventToRecord(event)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		records = append(records, record)
+	}
+	if len(records) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), s.Timeout)
+	defer cancel()
+	var errors []error
+	for _, result := range s.producer.ProduceSync(ctx, records...) {
+		if result.Err != nil {
+			errors = append(errors, trace.ConnectionProblem(result.Err,
+				"failed to publish record with key %q to %v", result.Record.Key, result.Record.Topic))
+		}
+	}
+	return trace.NewAggregate(errors...)
+}
+
+// Close flushes pending records and closes connections to brokers
+func (s *kafkaSink) Close() error {
+	s.producer.Close()
+	return nil
+}
+
+// eventToRecord converts the provided event to a Kafka record
+func (s *kafkaSink) eventToRecord(event types.Event) (*kgo.Record, error) {
+	grpcEvent, err := types.ToGRPCEvent(event)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	topic, ok := s.KindTopics[event.GetName()]
+	if !ok {
+		topic = s.Topic
+	}
+	return &kgo.Record{
+		Topic: topic,
+		Key:   []byte(event.GetAccountID()),
+		Value: grpcEvent.Data,
+		Headers: []kgo.RecordHeader{
+			{Key: kafkaHeaderEventID, Value: []byte(event.GetID())},
+			{Key: kafkaHeaderEventName, Value: []byte(event.GetName())},
+		},
+		Timestamp: event.GetMetadata().Created,
+	}, nil
+}
+
+const (
+	// kafkaTimeout is the default time to wait for records acknowledgement
+	kafkaTimeout = 10 * time.Second
+	// kafkaHeaderEventID is the record header with the event ID consumers
+	// may use to de-duplicate events retried by reporting clients
+	kafkaHeaderEventID = "event-id"
+	// kafkaHeaderEventName is the record header with the event name
+	kafkaHeaderEventName = "event-name"
+)
@@ -0,0 +1,114 @@
+/*
+Copyright 2017 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/gravitational/reporting"
+	"github.com/gravitational/reporting/types"
+
+	"github.com/google/uuid"
+	"github.com/twmb/franz-go/pkg/kgo"
+	check "gopkg.in/check.v1"
+)
+
+type KafkaSuite struct{}
+
+var _ = check.Suite(&KafkaSuite{})
+
+// TestKafka tests publishing events to Kafka
+func (s *KafkaSuite) TestKafka(c *check.C) {
+	producer := &testProducer{failTopic: "broken"}
+	sink := newKafkaSink(KafkaConfig{
+		Topic:      "events",
+		KindTopics: map[string]string{types.EventTypeUser: "users"},
+	}, producer)
+	serverEvent := types.NewServerLoginEvent(uuid.New().String())
+	serverEvent.SetAccountID("account-1")
+	userEvent := types.NewUserLoginEvent(uuid.New().String())
+	userEvent.SetAccountID("account-2")
+	err := sink.Put([]types.Event{serverEvent, userEvent})
+	c.Assert(err, check.IsNil)
+	c.Assert(len(producer.records), check.Equals, 2)
+	c.Assert(producer.records[0].Topic, check.Equals, "events")
+	c.Assert(string(producer.records[0].Key), check.Equals, "account-1")
+	c.Assert(producer.records[1].Topic, check.Equals, "users")
+	c.Assert(string(producer.records[1].Key), check.Equals, "account-2")
+	event, err := types.FromGRPCEvent(reporting.GRPCEvent{Data: producer.records[1].Value})
+	c.Assert(err, check.IsNil)
+	c.Assert(event, check.DeepEquals, userEvent)
+	// delivery errors are returned to the caller
+	sink.KindTopics[types.EventTypeUser] = "broken"
+	err = sink.Put([]types.Event{serverEvent, userEvent})
+	c.Assert(err, check.NotNil)
+}
+
+// TestKafkaBroker tests publishing events to a local Kafka broker, it is
+// skipped unless the broker address is provided in environment
+func (s *KafkaSuite) TestKafkaBroker(c *check.C) {
+	broker := os.Getenv(kafkaBrokerEnv)
+	if broker == "" {
+		c.Skip(fmt.Sprintf("%v is not set", kafkaBrokerEnv))
+	}
+	sink, err := NewKafkaSink(KafkaConfig{
+		Brokers: []string{broker},
+		Topic:   "reporting-test",
+	})
+	c.Assert(err, check.IsNil)
+	defer sink.Close()
+	err = sink.Put([]types.Event{
+		types.NewServerLoginEvent(uuid.New().String()),
+		types.NewUserLoginEvent(uuid.New().String()),
+	})
+	c.Assert(err, check.IsNil)
+}
+
+// testProducer is an in-process Kafka producer stand-in
+type testProducer struct {
+	// records are the successfully published records
+	records []*kgo.Record
+	// failTopic is the topic publishing to which fails
+	failTopic string
+}
+
+// ProduceSync records the provided records
+func (p *testProducer) ProduceSync(ctx context.Context, records ...*kgo.Record) kgo.ProduceResults {
+	var results kgo.ProduceResults
+	for _, record := range records {
+		if record.Topic == p.failTopic {
+			results = append(results, kgo.ProduceResult{
+				Record: record,
+				Err:    errors.New("unknown topic"),
+			})
+			continue
+		}
+		p.records = append(p.records, record)
+		results = append(results, kgo.ProduceResult{Record: record})
+	}
+	return results
+}
+
+// Close is a no-op
+func (p *testProducer) Close() {}
+
+// kafkaBrokerEnv is the environment variable with the address of a local
+// Kafka broker, e.g. localhost:9092
+const kafkaBrokerEnv = "REPORTING_KAFKA_BROKER"
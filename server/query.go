@@ -0,0 +1,235 @@
+/*
+Copyright 2017 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"sort"
+	"time"
+
+	"github.com/gravitational/reporting"
+	"github.com/gravitational/reporting/types"
+
+	"github.com/gravitational/trace"
+	log "github.com/sirupsen/logrus"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// Queryable is implemented by sinks that can answer event queries
+type Queryable interface {
+	// Query returns a page of events matching the provided filter
+	Query(EventFilter) (*QueryResult, error)
+}
+
+// QueryServerConfig defines the query server config
+type QueryServerConfig struct {
+	// Sink is the sink to query events from, it must implement Queryable
+	Sink Sink
+	// Admins is the list of common names of client certificates that
+	// are allowed to use the query server, the gRPC server must be
+	// configured to require and verify client certificates
+	Admins []string
+}
+
+// Check validates the config
+func (c QueryServerConfig) Check() error {
+	if c.Sink == nil {
+		return trace.BadParameter("missing Sink")
+	}
+	if _, ok := c.Sink.(Queryable); !ok {
+		return trace.BadParameter("sink %T does not support queries", c.Sink)
+	}
+	if len(c.Admins) == 0 {
+		return trace.BadParameter("missing Admins")
+	}
+	return nil
+}
+
+// NewQueryServer returns a new gRPC server for inspecting recorded events
+func NewQueryServer(config QueryServerConfig) (*queryServer, error) {
+	if err := config.Check(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &queryServer{
+		QueryServerConfig: config,
+		queryable:         config.Sink.(Queryable),
+	}, nil
+}
+
+type queryServer struct {
+	QueryServerConfig
+	queryable Queryable
+}
+
+// ListEvents returns a page of events matching the request filter
+func (s *queryServer) ListEvents(ctx context.Context, req *reporting.ListEventsRequest) (*reporting.ListEventsResponse, error) {
+	if err := s.authorize(ctx); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	filter := fromGRPCFilter(req.Filter)
+	filter.Limit = int(req.Limit)
+	filter.Cursor = req.Cursor
+	result, err := s.queryable.Query(filter)
+	if err != nil {
+		log.Error(trace.DebugReport(err))
+		return nil, trace.Wrap(err)
+	}
+	resp := &reporting.ListEventsResponse{Cursor: result.Cursor}
+	for _, event := range result.Events {
+		grpcEvent, err := types.ToGRPCEvent(event)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		resp.Events = append(resp.Events, grpcEvent)
+	}
+	return resp, nil
+}
+
+// CountEvents returns numbers of events matching the request filter
+// grouped by the requested field and period
+func (s *queryServer) CountEvents(ctx context.Context, req *reporting.CountEventsRequest) (*reporting.CountEventsResponse, error) {
+	if err := s.authorize(ctx); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	key, err := groupKey(req.GroupBy)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	counts := make(map[eventGroup]int64)
+	filter := fromGRPCFilter(req.Filter)
+	filter.Limit = storeMaxQueryLimit
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, trace.Wrap(err)
+		}
+		result, err := s.queryable.Query(filter)
+		if err != nil {
+			log.Error(trace.DebugReport(err))
+			return nil, trace.Wrap(err)
+		}
+		for _, event := range result.Events {
+			start, err := periodStart(event.GetMetadata().Created, req.Period)
+			if err != nil {
+				return nil, trace.Wrap(err)
+			}
+			counts[eventGroup{key: key(event), start: start}]++
+		}
+		if result.Cursor == "" {
+			break
+		}
+		filter.Cursor = result.Cursor
+	}
+	groups := make([]eventGroup, 0, len(counts))
+	for group := range counts {
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if !groups[i].start.Equal(groups[j].start) {
+			return groups[i].start.Before(groups[j].start)
+		}
+		return groups[i].key < groups[j].key
+	})
+	resp := &reporting.CountEventsResponse{}
+	for _, group := range groups {
+		resp.Counts = append(resp.Counts, &reporting.EventCount{
+			Key:   group.key,
+			Start: group.start.UnixNano(),
+			Count: counts[group],
+		})
+	}
+	return resp, nil
+}
+
+// authorize checks that the caller has presented an admin certificate
+func (s *queryServer) authorize(ctx context.Context) error {
+	return authorizeAdmin(ctx, s.Admins)
+}
+
+// authorizeAdmin checks that the caller has presented a verified client
+// certificate with one of the provided admin common names
+func authorizeAdmin(ctx context.Context, admins []string) error {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return trace.AccessDenied("missing peer information")
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return trace.AccessDenied("missing verified client certificate")
+	}
+	name := info.State.VerifiedChains[0][0].Subject.CommonName
+	for _, admin := range admins {
+		if name == admin {
+			return nil
+		}
+	}
+	return trace.AccessDenied("%q is not an admin", name)
+}
+
+// eventGroup identifies a single group of counted events
+type eventGroup struct {
+	// key is the value of the grouped field
+	key string
+	// start is the start of the period
+	start time.Time
+}
+
+// fromGRPCFilter converts the gRPC event filter to the store filter
+func fromGRPCFilter(filter *reporting.EventFilter) EventFilter {
+	if filter == nil {
+		return EventFilter{}
+	}
+	result := EventFilter{
+		AccountID: filter.AccountID,
+		Name:      filter.Name,
+		Action:    filter.Action,
+	}
+	if filter.From != 0 {
+		result.From = time.Unix(0, filter.From).UTC()
+	}
+	if filter.To != 0 {
+		result.To = time.Unix(0, filter.To).UTC()
+	}
+	return result
+}
+
+// groupKey returns the function that extracts the grouped field from events
+func groupKey(groupBy reporting.GroupBy) (func(types.Event) string, error) {
+	switch groupBy {
+	case reporting.GroupBy_ACCOUNT:
+		return types.Event.GetAccountID, nil
+	case reporting.GroupBy_ACTION:
+		return types.Event.GetAction, nil
+	}
+	return nil, trace.BadParameter("unsupported group by %v", groupBy)
+}
+
+// periodStart returns the start of the period the provided time belongs to
+func periodStart(t time.Time, period reporting.Period) (time.Time, error) {
+	year, month, day := t.UTC().Date()
+	switch period {
+	case reporting.Period_DAY:
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC), nil
+	case reporting.Period_WEEK:
+		// weeks start on Monday
+		offset := (int(t.UTC().Weekday()) + 6) % 7
+		return time.Date(year, month, day-offset, 0, 0, 0, 0, time.UTC), nil
+	case reporting.Period_MONTH:
+		return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC), nil
+	}
+	return time.Time{}, trace.BadParameter("unsupported period %v", period)
+}
@@ -17,9 +17,19 @@ limitations under the License.
 package server
 
 import (
+	"bytes"
 	"context"
+	"crypto/rand"
 	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
 	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -27,9 +37,14 @@ import (
 	rclient "github.com/gravitational/reporting/client"
 	"github.com/gravitational/reporting/types"
 
+	"cloud.google.com/go/bigquery"
 	"github.com/cloudflare/cfssl/csr"
+	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/gravitational/license/authority"
+	"github.com/gravitational/trace"
+	"github.com/gravitational/trace/trail"
 	"github.com/google/uuid"
+	"golang.org/x/crypto/ed25519"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	check "gopkg.in/check.v1"
@@ -38,6 +53,7 @@ import (
 func TestReporting(t *testing.T) { check.TestingT(t) }
 
 type ReportingSuite struct {
+	addr     string
 	client   rclient.Client
 	eventsCh chan types.Event
 }
@@ -46,8 +62,8 @@ var _ = check.Suite(&ReportingSuite{})
 
 func (r *ReportingSuite) SetUpSuite(c *check.C) {
 	r.eventsCh = make(chan types.Event, 10)
-	serverAddr := startTestServer(c, r.eventsCh)
-	r.client = getTestClient(c, serverAddr)
+	r.addr = startTestServer(c, r.eventsCh)
+	r.client = getTestClient(c, r.addr)
 }
 
 // TestReporting tests real client/server communication
@@ -72,19 +88,837 @@ func (r *ReportingSuite) TestReporting(c *check.C) {
 	c.Assert(received, check.DeepEquals, events)
 }
 
+// TestSnapshots tests periodically recording gathered snapshots
+func (r *ReportingSuite) TestSnapshots(c *check.C) {
+	// snapshots recorded after the reporter is stopped may still be
+	// flushed so the test uses its own server and client
+	eventsCh := make(chan types.Event, 100)
+	client := getTestClient(c, startTestServer(c, eventsCh))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	err := rclient.StartSnapshots(ctx, rclient.SnapshotConfig{
+		Client: client,
+		Gather: func(context.Context) (*types.SnapshotEvent, error) {
+			return types.NewSnapshotEvent("1.0.0", map[string]int64{types.GaugeUsers: 1}), nil
+		},
+		Interval: 100 * time.Millisecond,
+	})
+	c.Assert(err, check.IsNil)
+	for i := 0; i < 2; i++ {
+		select {
+		case e := <-eventsCh:
+			c.Assert(e.GetName(), check.Equals, types.EventTypeSnapshot)
+		case <-time.After(testTimeout):
+			c.Fatal("timeout waiting for snapshots")
+		}
+	}
+	cancel()
+	err = rclient.StartSnapshots(ctx, rclient.SnapshotConfig{Client: client})
+	c.Assert(err, check.NotNil)
+}
+
+// TestHeartbeatWatcher tests fetching heartbeats with the interval
+// recommended by the control plane
+func (r *ReportingSuite) TestHeartbeatWatcher(c *check.C) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	fetchedCh := make(chan *types.Heartbeat, 10)
+	watcher, err := rclient.WatchHeartbeats(ctx, rclient.HeartbeatConfig{
+		Fetch: func(context.Context) (*types.Heartbeat, error) {
+			heartbeat := types.NewHeartbeat()
+			heartbeat.Spec.Features = []string{"sso"}
+			heartbeat.Spec.PollInterval = 50 * time.Millisecond
+			return heartbeat, nil
+		},
+		OnHeartbeat: func(heartbeat *types.Heartbeat) {
+			fetchedCh <- heartbeat
+		},
+		// heartbeats are fetched again only if their interval is honored
+		Interval:    time.Hour,
+		MinInterval: 10 * time.Millisecond,
+	})
+	c.Assert(err, check.IsNil)
+	for i := 0; i < 3; i++ {
+		select {
+		case <-fetchedCh:
+		case <-time.After(testTimeout):
+			c.Fatal("timeout waiting for heartbeats")
+		}
+	}
+	c.Assert(watcher.HasFeature("sso"), check.Equals, true)
+	c.Assert(watcher.HasFeature("audit"), check.Equals, false)
+	_, err = rclient.WatchHeartbeats(ctx, rclient.HeartbeatConfig{})
+	c.Assert(err, check.NotNil)
+}
+
+// TestActiveNotifications tests filtering out inactive and dismissed
+// notifications
+func (r *ReportingSuite) TestActiveNotifications(c *check.C) {
+	now := time.Now().UTC()
+	notification := func(id string, dismissible bool, notBefore, notAfter time.Time) types.Notification {
+		return types.Notification{
+			Type:        types.NotificationUsage,
+			Severity:    types.SeverityInfo,
+			Text:        id,
+			ID:          id,
+			NotBefore:   notBefore,
+			NotAfter:    notAfter,
+			Dismissible: dismissible,
+		}
+	}
+	heartbeat := types.NewHeartbeat(
+		notification("active", true, time.Time{}, time.Time{}),
+		notification("active", true, time.Time{}, time.Time{}),
+		notification("dismissed", true, now.Add(-time.Hour), time.Time{}),
+		notification("pending", false, now.Add(time.Hour), time.Time{}),
+		notification("expired", false, time.Time{}, now.Add(-time.Hour)),
+		notification("required", false, time.Time{}, now.Add(time.Hour)),
+	)
+	dismissals := rclient.NewDismissals()
+	c.Assert(dismissals.Dismiss(heartbeat.Spec.Notifications[2]), check.IsNil)
+	c.Assert(dismissals.Dismiss(heartbeat.Spec.Notifications[5]), check.NotNil)
+	var ids []string
+	for _, n := range rclient.ActiveNotifications(heartbeat, now, dismissals) {
+		ids = append(ids, n.ID)
+	}
+	c.Assert(ids, check.DeepEquals, []string{"active", "required"})
+}
+
+// TestSignedEvents tests verifying event signatures with registered
+// installation keys
+func (r *ReportingSuite) TestSignedEvents(c *check.C) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	c.Assert(err, check.IsNil)
+	_, otherPrivate, err := ed25519.GenerateKey(rand.Reader)
+	c.Assert(err, check.IsNil)
+	verifier := &VerifierConfig{Keys: map[string]InstallationKey{
+		"installation": {PublicKey: public, AccountID: "account"},
+	}}
+	eventsCh := make(chan types.Event, 10)
+	addr := startTestGRPCServer(c, newTestServer(c, ServerConfig{
+		Sinks:    []Sink{NewChannelSink(eventsCh)},
+		Verifier: verifier,
+	}))
+	for _, tc := range []struct {
+		key     *types.SigningKey
+		account string
+		status  string
+	}{
+		{key: &types.SigningKey{ID: "installation", PrivateKey: private}, account: "account", status: types.SignatureValid},
+		{key: &types.SigningKey{ID: "installation", PrivateKey: otherPrivate}, account: "account", status: types.SignatureInvalid},
+		{key: &types.SigningKey{ID: "other", PrivateKey: private}, account: "account", status: types.SignatureInvalid},
+		// keys can only sign events of their account
+		{key: &types.SigningKey{ID: "installation", PrivateKey: private}, account: "other", status: types.SignatureInvalid},
+		{account: "account", status: types.SignatureMissing},
+	} {
+		client, err := rclient.NewClient(context.Background(), rclient.ClientConfig{
+			ServerAddr: addr,
+			Insecure:   true,
+			SigningKey: tc.key,
+		})
+		c.Assert(err, check.IsNil)
+		event := types.NewServerLoginEvent(uuid.New().String())
+		event.SetAccountID(tc.account)
+		client.Record(event)
+		select {
+		case e := <-eventsCh:
+			c.Assert(signatureStatus(e), check.Equals, tc.status)
+		case <-time.After(testTimeout):
+			c.Fatal("timeout waiting for events")
+		}
+	}
+
+	// unverified events that can't be flagged are rejected
+	grpcEvent, err := types.ToGRPCEvent(types.NewServerLoginEvent(uuid.New().String()))
+	c.Assert(err, check.IsNil)
+	err = verifyEvent(*verifier, *grpcEvent, unlabeledEvent{types.NewServerLoginEvent("server")})
+	c.Assert(trace.IsBadParameter(err), check.Equals, true)
+
+	// invalid verifier config is rejected
+	_, err = NewServerWithConfig(ServerConfig{Verifier: &VerifierConfig{Keys: map[string]InstallationKey{
+		"installation": {PublicKey: public},
+	}}})
+	c.Assert(trace.IsBadParameter(err), check.Equals, true)
+
+	// unverified events are dropped in strict mode
+	verifier.Reject = true
+	server := newTestServer(c, ServerConfig{
+		Sinks:    []Sink{NewChannelSink(eventsCh)},
+		Verifier: verifier,
+	})
+	unsigned, err := types.ToGRPCEvent(types.NewServerLoginEvent(uuid.New().String()))
+	c.Assert(err, check.IsNil)
+	event := types.NewServerLoginEvent(uuid.New().String())
+	event.SetAccountID("account")
+	signed, err := types.ToGRPCEvent(event)
+	c.Assert(err, check.IsNil)
+	c.Assert(types.SignGRPCEvent(signed, types.SigningKey{ID: "installation", PrivateKey: private}), check.IsNil)
+	_, err = server.Record(context.Background(), &reporting.GRPCEvents{Events: []*reporting.GRPCEvent{unsigned, signed}})
+	c.Assert(err, check.IsNil)
+	e := <-eventsCh
+	c.Assert(e.GetID(), check.Equals, event.GetID())
+	c.Assert(signatureStatus(e), check.Equals, types.SignatureValid)
+	c.Assert(len(eventsCh), check.Equals, 0)
+}
+
+// TestForgedSignatureStatus tests that events claiming to have been
+// verified are rejected by servers with and without a verifier
+func (r *ReportingSuite) TestForgedSignatureStatus(c *check.C) {
+	public, _, err := ed25519.GenerateKey(rand.Reader)
+	c.Assert(err, check.IsNil)
+	for _, verifier := range []*VerifierConfig{nil, {
+		Keys: map[string]InstallationKey{
+			"installation": {PublicKey: public, AccountID: "account"},
+		},
+	}} {
+		eventsCh := make(chan types.Event, 10)
+		server := newTestServer(c, ServerConfig{
+			Sinks:    []Sink{NewChannelSink(eventsCh)},
+			Verifier: verifier,
+		})
+		forged := types.NewServerLoginEvent(uuid.New().String())
+		forged.SetLabel(types.LabelSignature, types.SignatureValid)
+		event := types.NewServerLoginEvent(uuid.New().String())
+		var grpcEvents reporting.GRPCEvents
+		for _, e := range []types.Event{forged, event} {
+			grpcEvent, err := types.ToGRPCEvent(e)
+			c.Assert(err, check.IsNil)
+			grpcEvents.Events = append(grpcEvents.Events, grpcEvent)
+		}
+		_, err := server.Record(context.Background(), &grpcEvents)
+		c.Assert(err, check.IsNil)
+		e := <-eventsCh
+		c.Assert(e.GetID(), check.Equals, event.GetID())
+		c.Assert(signatureStatus(e) == types.SignatureValid, check.Equals, false)
+		c.Assert(len(eventsCh), check.Equals, 0)
+	}
+}
+
+// unlabeledEvent is an event that does not support labels
+type unlabeledEvent struct {
+	types.Event
+}
+
+// TestInvalidEvents tests that invalid events are dropped without failing
+// the rest of the batch
+func (r *ReportingSuite) TestInvalidEvents(c *check.C) {
+	eventsCh := make(chan types.Event, 10)
+	server := newTestServer(c, ServerConfig{
+		Sinks:      []Sink{NewChannelSink(eventsCh)},
+		Validation: types.ValidationConfig{MaxAge: time.Hour},
+	})
+	stale := types.NewServerLoginEvent(uuid.New().String())
+	stale.Metadata.Created = time.Now().Add(-2 * time.Hour)
+	event := types.NewServerLoginEvent(uuid.New().String())
+	var grpcEvents reporting.GRPCEvents
+	for _, e := range []types.Event{stale, event} {
+		grpcEvent, err := types.ToGRPCEvent(e)
+		c.Assert(err, check.IsNil)
+		grpcEvents.Events = append(grpcEvents.Events, grpcEvent)
+	}
+	grpcEvents.Events = append(grpcEvents.Events, &reporting.GRPCEvent{Data: []byte(`{"kind": "event"}`)})
+	_, err := server.Record(context.Background(), &grpcEvents)
+	c.Assert(err, check.IsNil)
+	c.Assert(<-eventsCh, check.DeepEquals, event)
+	c.Assert(len(eventsCh), check.Equals, 0)
+
+	_, err = NewServerWithConfig(ServerConfig{Validation: types.ValidationConfig{MaxAge: -1}})
+	c.Assert(trace.IsBadParameter(err), check.Equals, true)
+	c.Assert(func() { NewServer(ServerConfig{Validation: types.ValidationConfig{MaxAge: -1}}) },
+		check.PanicMatches, "(?s).*max age.*")
+}
+
+// TestRejectedEvents tests that the client discards events the server
+// rejects as invalid instead of retrying them
+func (r *ReportingSuite) TestRejectedEvents(c *check.C) {
+	eventsCh := make(chan types.Event, 10)
+	rejectingServer := &rejectingServer{
+		server:   newTestServer(c, ServerConfig{Sinks: []Sink{NewChannelSink(eventsCh)}}),
+		rejected: make(chan struct{}),
+	}
+	client := getTestClient(c, startTestGRPCServer(c, rejectingServer))
+	client.Record(types.NewServerLoginEvent(uuid.New().String()))
+	select {
+	case <-rejectingServer.rejected:
+	case <-time.After(testTimeout):
+		c.Fatal("timeout waiting for events")
+	}
+	event := types.NewServerLoginEvent(uuid.New().String())
+	client.Record(event)
+	select {
+	case e := <-eventsCh:
+		c.Assert(e, check.DeepEquals, event)
+	case <-time.After(testTimeout):
+		c.Fatal("timeout waiting for events")
+	}
+}
+
+// rejectingServer emulates reporting server that rejects the first batch
+// of events as invalid
+type rejectingServer struct {
+	server   reporting.EventsServiceServer
+	rejected chan struct{}
+	once     sync.Once
+}
+
+// Record rejects the first batch and records the following batches
+func (s *rejectingServer) Record(ctx context.Context, grpcEvents *reporting.GRPCEvents) (*empty.Empty, error) {
+	var err error
+	s.once.Do(func() {
+		err = trail.ToGRPC(trace.BadParameter("invalid events"))
+		close(s.rejected)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.server.Record(ctx, grpcEvents)
+}
+
+// TailEvents streams recorded events
+func (s *rejectingServer) TailEvents(filter *reporting.EventFilter, stream reporting.EventsService_TailEventsServer) error {
+	return s.server.TailEvents(filter, stream)
+}
+
+// TestPseudonymization tests replacing event identifiers with pseudonyms
+// that are consistent across client restarts
+func (r *ReportingSuite) TestPseudonymization(c *check.C) {
+	secretPath := filepath.Join(c.MkDir(), "secret")
+	var pseudonyms []string
+	for i := 0; i < 2; i++ {
+		client, err := rclient.NewClient(context.Background(), rclient.ClientConfig{
+			ServerAddr:          r.addr,
+			Insecure:            true,
+			PseudonymSecretPath: secretPath,
+		})
+		c.Assert(err, check.IsNil)
+		client.Record(types.NewServerLoginEvent("node-1.example.com"))
+		select {
+		case e := <-r.eventsCh:
+			serverID := e.(*types.ServerEvent).Spec.ServerID
+			c.Assert(serverID, check.Not(check.Equals), "node-1.example.com")
+			pseudonyms = append(pseudonyms, serverID)
+		case <-time.After(testTimeout):
+			c.Fatal("timeout waiting for events")
+		}
+	}
+	c.Assert(pseudonyms[0], check.Equals, pseudonyms[1])
+	p, err := rclient.LoadPseudonymizer(secretPath)
+	c.Assert(err, check.IsNil)
+	c.Assert(p.Pseudonym("node-1.example.com"), check.Equals, pseudonyms[0])
+
+	// recording the same event twice pseudonymizes it once
+	client, err := rclient.NewClient(context.Background(), rclient.ClientConfig{
+		ServerAddr:          r.addr,
+		Insecure:            true,
+		PseudonymSecretPath: secretPath,
+	})
+	c.Assert(err, check.IsNil)
+	serverEvent := types.NewServerLoginEvent("node-1.example.com")
+	for i := 0; i < 2; i++ {
+		client.Record(serverEvent)
+		select {
+		case e := <-r.eventsCh:
+			c.Assert(e.(*types.ServerEvent).Spec.ServerID, check.Equals, pseudonyms[0])
+		case <-time.After(testTimeout):
+			c.Fatal("timeout waiting for events")
+		}
+	}
+	c.Assert(serverEvent.Spec.ServerID, check.Equals, "node-1.example.com")
+
+	event := types.NewActionEvent("user", "alice", types.EventActionCreate, nil)
+	pseudonymized, err := p.Pseudonymize(event)
+	c.Assert(err, check.IsNil)
+	c.Assert(pseudonymized.(*types.ActionEvent).Spec.SubjectID, check.Equals, p.Pseudonym("alice"))
+	c.Assert(event.Spec.SubjectID, check.Equals, "alice")
+	other, err := rclient.LoadPseudonymizer(filepath.Join(c.MkDir(), "secret"))
+	c.Assert(err, check.IsNil)
+	c.Assert(other.Pseudonym("alice"), check.Not(check.Equals), p.Pseudonym("alice"))
+
+	// concurrently loaded pseudonymizers share the generated secret
+	secretPath = filepath.Join(c.MkDir(), "secret")
+	pseudonymsCh := make(chan string, 10)
+	for i := 0; i < cap(pseudonymsCh); i++ {
+		go func() {
+			p, err := rclient.LoadPseudonymizer(secretPath)
+			if err != nil {
+				pseudonymsCh <- err.Error()
+				return
+			}
+			pseudonymsCh <- p.Pseudonym("alice")
+		}()
+	}
+	pseudonym := <-pseudonymsCh
+	for i := 1; i < cap(pseudonymsCh); i++ {
+		c.Assert(<-pseudonymsCh, check.Equals, pseudonym)
+	}
+	files, err := ioutil.ReadDir(filepath.Dir(secretPath))
+	c.Assert(err, check.IsNil)
+	c.Assert(files, check.HasLen, 1)
+}
+
+// TestConsent tests dropping events the client has no consent to report
+func (r *ReportingSuite) TestConsent(c *check.C) {
+	client, err := rclient.NewClient(context.Background(), rclient.ClientConfig{
+		ServerAddr:  r.addr,
+		Insecure:    true,
+		AllowEvents: []string{types.EventTypeServer, types.EventTypeUser},
+		DenyEvents:  []string{types.EventTypeUser},
+	})
+	c.Assert(err, check.IsNil)
+	client.Record(types.NewUserLoginEvent("user"))
+	client.Record(types.NewActionEvent("user", "user", types.EventActionCreate, nil))
+	client.Record(types.NewServerLoginEvent("server"))
+	r.expectEvents(c, types.EventTypeServer)
+
+	client.SetConsent(false)
+	client.SetConsent(false)
+	client.Record(types.NewServerLoginEvent("server"))
+	r.expectEvents(c, types.EventTypeConsent)
+	client.SetConsent(true)
+	client.Record(types.NewServerLoginEvent("server"))
+	r.expectEvents(c, types.EventTypeServer)
+
+	// environment variable overrides the config
+	os.Setenv(rclient.DisableEnvVar, "true")
+	defer os.Unsetenv(rclient.DisableEnvVar)
+	client, err = rclient.NewClient(context.Background(), rclient.ClientConfig{
+		ServerAddr:       r.addr,
+		Insecure:         true,
+		ConsentStatePath: filepath.Join(c.MkDir(), "consent"),
+	})
+	c.Assert(err, check.IsNil)
+	client.Record(types.NewServerLoginEvent("server"))
+	r.expectEvents(c, types.EventTypeConsent)
+	os.Setenv(rclient.DisableEnvVar, "maybe")
+	_, err = rclient.NewClient(context.Background(), rclient.ClientConfig{ServerAddr: r.addr})
+	c.Assert(trace.IsBadParameter(err), check.Equals, true)
+}
+
+// TestOptOutState tests that the opt-out is recorded once rather than on
+// every client start
+func (r *ReportingSuite) TestOptOutState(c *check.C) {
+	eventsCh := make(chan types.Event, 10)
+	addr := startTestGRPCServer(c, newTestServer(c, ServerConfig{
+		Sinks: []Sink{NewChannelSink(eventsCh)},
+	}))
+	secretPath := filepath.Join(c.MkDir(), "secret")
+	newClient := func(disabled bool) rclient.Client {
+		client, err := rclient.NewClient(context.Background(), rclient.ClientConfig{
+			ServerAddr:          addr,
+			Insecure:            true,
+			PseudonymSecretPath: secretPath,
+			Disabled:            disabled,
+		})
+		c.Assert(err, check.IsNil)
+		return client
+	}
+	expectOptOut := func() {
+		select {
+		case e := <-eventsCh:
+			c.Assert(e.GetName(), check.Equals, types.EventTypeConsent)
+		case <-time.After(testTimeout):
+			c.Fatal("timeout waiting for events")
+		}
+		// the opt-out is saved after the server has received it
+		for start := time.Now(); ; time.Sleep(100 * time.Millisecond) {
+			if time.Since(start) > testTimeout {
+				c.Fatal("timeout waiting for consent state")
+			}
+			data, err := ioutil.ReadFile(secretPath + ".consent")
+			if err == nil && string(data) == "revoked" {
+				return
+			}
+		}
+	}
+
+	newClient(true)
+	expectOptOut()
+	client := newClient(true)
+	client.Record(types.NewServerLoginEvent("server"))
+	// wait longer than the client flush interval
+	select {
+	case e := <-eventsCh:
+		c.Fatalf("unexpected event %v", e.GetName())
+	case <-time.After(4 * time.Second):
+	}
+
+	// the opt-out is recorded again once consent has been granted
+	newClient(false)
+	newClient(true)
+	expectOptOut()
+
+	// the opt-out is not recorded if it can't be saved
+	_, err := rclient.NewClient(context.Background(), rclient.ClientConfig{
+		ServerAddr: addr,
+		Insecure:   true,
+		Disabled:   true,
+	})
+	c.Assert(err, check.IsNil)
+	select {
+	case e := <-eventsCh:
+		c.Fatalf("unexpected event %v", e.GetName())
+	case <-time.After(4 * time.Second):
+	}
+}
+
+// TestTransparencyLog tests logging of sent event batches
+func (r *ReportingSuite) TestTransparencyLog(c *check.C) {
+	eventsCh := make(chan types.Event, 10)
+	addr := startTestGRPCServer(c, newTestServer(c, ServerConfig{
+		Sinks: []Sink{NewChannelSink(eventsCh)},
+	}))
+	path := filepath.Join(c.MkDir(), "transparency.log")
+	client, err := rclient.NewClient(context.Background(), rclient.ClientConfig{
+		ServerAddr:      addr,
+		Insecure:        true,
+		TransparencyLog: &rclient.TransparencyLogConfig{Path: path},
+	})
+	c.Assert(err, check.IsNil)
+	event := types.NewServerLoginEvent("server")
+	client.Record(event)
+	select {
+	case <-eventsCh:
+	case <-time.After(testTimeout):
+		c.Fatal("timeout waiting for events")
+	}
+	// the batch is logged after the server has received it
+	var records []rclient.TransparencyRecord
+	for start := time.Now(); len(records) == 0; time.Sleep(100 * time.Millisecond) {
+		if time.Since(start) > testTimeout {
+			c.Fatal("timeout waiting for transparency log")
+		}
+		records, err = rclient.ReadTransparencyLog(path)
+		c.Assert(err, check.IsNil)
+	}
+	c.Assert(records, check.HasLen, 1)
+	c.Assert(records[0].Server, check.Equals, addr)
+	c.Assert(records[0].Version, check.Equals, types.ResourceVersion)
+	c.Assert(records[0].Events, check.HasLen, 1)
+	var sent types.ServerEvent
+	c.Assert(json.Unmarshal(records[0].Events[0], &sent), check.IsNil)
+	c.Assert(sent.Spec.ID, check.Equals, event.Spec.ID)
+
+	// rotated files are read oldest first and the oldest file is dropped
+	path = filepath.Join(c.MkDir(), "transparency.log")
+	transparency, err := rclient.NewTransparencyLog(rclient.TransparencyLogConfig{
+		Path:       path,
+		MaxSize:    1,
+		MaxBackups: 2,
+	})
+	c.Assert(err, check.IsNil)
+	for _, server := range []string{"a", "b", "c", "d"} {
+		c.Assert(transparency.Write(rclient.TransparencyRecord{Server: server}), check.IsNil)
+	}
+	c.Assert(transparency.Close(), check.IsNil)
+	records, err = rclient.ReadTransparencyLog(path)
+	c.Assert(err, check.IsNil)
+	var servers []string
+	for _, record := range records {
+		servers = append(servers, record.Server)
+	}
+	c.Assert(servers, check.DeepEquals, []string{"b", "c", "d"})
+}
+
+// expectEvents waits for events with the provided names and checks that
+// no other events are received
+func (r *ReportingSuite) expectEvents(c *check.C, names ...string) {
+	var received []string
+	for range names {
+		select {
+		case e := <-r.eventsCh:
+			received = append(received, e.GetName())
+		case <-time.After(testTimeout):
+			c.Fatalf("timeout waiting for events %v, received %v", names, received)
+		}
+	}
+	c.Assert(received, check.DeepEquals, names)
+	select {
+	case e := <-r.eventsCh:
+		c.Fatalf("unexpected event %v", e.GetName())
+	default:
+	}
+}
+
+// TestVersionFallback tests sending events to servers that do not support
+// the current resource version
+func (r *ReportingSuite) TestVersionFallback(c *check.C) {
+	for _, legacy := range []bool{false, true} {
+		eventsCh := make(chan types.Event, 10)
+		addr := startTestGRPCServer(c, &v2Server{
+			server: newTestServer(c, ServerConfig{
+				Sinks: []Sink{NewChannelSink(eventsCh)},
+			}),
+			legacy: legacy,
+		})
+		client := getTestClient(c, addr)
+		event := types.NewUserLoginEvent(uuid.New().String())
+		client.Record(event)
+		select {
+		case e := <-eventsCh:
+			c.Assert(e, check.DeepEquals, event)
+		case <-time.After(testTimeout):
+			c.Fatal("timeout waiting for events")
+		}
+	}
+}
+
+// v2Server emulates reporting server that only supports v2 resources
+type v2Server struct {
+	server reporting.EventsServiceServer
+	// legacy is whether the server predates versioned resources and
+	// rejects other versions with a plain error message
+	legacy bool
+}
+
+// Record rejects events that are not v2 the same way older servers do
+func (s *v2Server) Record(ctx context.Context, grpcEvents *reporting.GRPCEvents) (*empty.Empty, error) {
+	for _, grpcEvent := range grpcEvents.Events {
+		var header struct {
+			Version string `json:"version"`
+		}
+		if err := json.Unmarshal(grpcEvent.Data, &header); err != nil {
+			return nil, trace.Wrap(err)
+		}
+		if header.Version != types.V2 && s.legacy {
+			return nil, trace.BadParameter("expected resource version %q, got %q",
+				types.V2, header.Version)
+		}
+		if header.Version != types.V2 {
+			return nil, rejectVersion(ctx, &types.VersionError{Version: header.Version})
+		}
+	}
+	return s.server.Record(ctx, grpcEvents)
+}
+
+// TailEvents streams recorded events
+func (s *v2Server) TailEvents(filter *reporting.EventFilter, stream reporting.EventsService_TailEventsServer) error {
+	return s.server.TailEvents(filter, stream)
+}
+
 // TestBQStructSavers tests converting events to BigQuery struct savers
 func (r *ReportingSuite) TestBQStructSavers(c *check.C) {
 	event1 := types.NewServerLoginEvent(uuid.New().String())
 	event2 := types.NewUserLoginEvent(uuid.New().String())
-	savers := eventsToStructSavers([]types.Event{event1, event2})
-	c.Assert(len(savers), check.Equals, 2)
+	event3 := types.NewActionEvent("user", uuid.New().String(), types.EventActionLogout,
+		map[string]string{"method": "sso", "connector": "github"})
+	event4 := types.NewUsageEvent("account", uuid.New().String(), "session.duration", "12.5", "minutes",
+		time.Now().Add(-time.Hour), time.Now())
+	savers := eventsToStructSavers([]types.Event{event1, event2, event3, event4})
+	c.Assert(len(savers), check.Equals, 4)
 	c.Assert(savers[0].InsertID, check.Equals, event1.Spec.ID)
 	c.Assert(savers[1].InsertID, check.Equals, event2.Spec.ID)
+	c.Assert(savers[2].InsertID, check.Equals, event3.Spec.ID)
+	c.Assert(savers[2].Struct.(bqActionEvent).Labels, check.DeepEquals, []bqLabel{
+		{Key: "connector", Value: "github"},
+		{Key: "method", Value: "sso"},
+	})
+	c.Assert(savers[3].InsertID, check.Equals, event4.Spec.ID)
+	c.Assert(savers[3].Struct.(bqUsageEvent).Quantity, check.Equals, "12.5")
+
+	// signature status is stored for all events
+	event1.SetLabel(types.LabelSignature, types.SignatureInvalid)
+	saver, err := eventToStructSaver(event1)
+	c.Assert(err, check.IsNil)
+	c.Assert(saver.Struct.(bqServerEvent).Signature, check.Equals, types.SignatureInvalid)
+}
+
+// TestBQLoadRows tests converting events to BigQuery load job source
+func (r *ReportingSuite) TestBQLoadRows(c *check.C) {
+	event1 := types.NewServerLoginEvent(uuid.New().String())
+	event2 := types.NewUserLoginEvent(uuid.New().String())
+	batches, err := (&bigQuerySink{}).batches([]types.Event{event1, event2})
+	c.Assert(err, check.IsNil)
+	c.Assert(len(batches), check.Equals, 1)
+	c.Assert(batches[0].table, check.Equals, bqTableName)
+	c.Assert(batches[0].insertIDs, check.DeepEquals, []string{event1.Spec.ID, event2.Spec.ID})
+	var buf bytes.Buffer
+	c.Assert(rowsToNDJSON(batches[0].rows, &buf), check.IsNil)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	c.Assert(len(lines), check.Equals, 2)
+	var row1 bqServerEvent
+	c.Assert(json.Unmarshal([]byte(lines[0]), &row1), check.IsNil)
+	c.Assert(row1.ServerID, check.Equals, event1.Spec.ServerID)
+	c.Assert(row1.Time.Equal(event1.Metadata.Created.Truncate(time.Second)), check.Equals, true)
+	var row2 bqUserEvent
+	c.Assert(json.Unmarshal([]byte(lines[1]), &row2), check.IsNil)
+	c.Assert(row2.UserID, check.Equals, event2.Spec.UserID)
+}
+
+// TestBQEnqueue tests that buffering events for load jobs never blocks
+// and is limited by the buffer size
+func (r *ReportingSuite) TestBQEnqueue(c *check.C) {
+	sink := &bigQuerySink{
+		BigQueryConfig: BigQueryConfig{LoadBatchSize: 1},
+		loadCh:         make(chan struct{}, 1),
+	}
+	events := make([]types.Event, sink.maxBufferedEvents())
+	c.Assert(sink.enqueue(events[:1]), check.IsNil)
+	c.Assert(sink.enqueue(events[:1]), check.IsNil)
+	c.Assert(len(sink.loadCh), check.Equals, 1)
+	err := sink.enqueue(events)
+	c.Assert(trace.IsLimitExceeded(err), check.Equals, true)
+	c.Assert(sink.buffer, check.HasLen, 2)
+}
+
+// TestBQLoadJobID tests that load job IDs are stable for the same batch
+func (r *ReportingSuite) TestBQLoadJobID(c *check.C) {
+	ids := []string{uuid.New().String(), uuid.New().String()}
+	c.Assert(loadJobID(bqTableName, ids), check.Equals, loadJobID(bqTableName, ids))
+	c.Assert(loadJobID(bqTableName, ids), check.Not(check.Equals), loadJobID(bqTableName, ids[:1]))
+	c.Assert(loadJobID(bqTableName, ids), check.Not(check.Equals), loadJobID("other", ids))
+	// batches get a new job ID only after their load job has failed
+	batch := &bqBatch{table: bqTableName, insertIDs: ids}
+	c.Assert(batch.jobID(), check.Equals, loadJobID(bqTableName, ids))
+	batch.attempt++
+	c.Assert(batch.jobID(), check.Not(check.Equals), loadJobID(bqTableName, ids))
+	// only transient job errors are retried
+	c.Assert(isRetryableJobError(&bigquery.Error{Reason: "backendError"}), check.Equals, true)
+	c.Assert(isRetryableJobError(&bigquery.Error{Reason: "invalid"}), check.Equals, false)
+}
+
+// TestBQKindTables tests deriving per-kind table schemas and rows from events
+func (r *ReportingSuite) TestBQKindTables(c *check.C) {
+	c.Assert(kindTableName(types.EventTypeServer), check.Equals, "events_server")
+	c.Assert(kindTableName("session.start"), check.Equals, "events_session_start")
+	event := types.NewServerLoginEvent(uuid.New().String())
+	spec, err := eventSpec(event)
+	c.Assert(err, check.IsNil)
+	schema, err := kindTableSchema(spec.Type())
+	c.Assert(err, check.IsNil)
+	var names []string
+	for _, field := range schema {
+		names = append(names, field.Name)
+	}
+	c.Assert(names, check.DeepEquals, []string{"time", "signature", "id", "action", "accountID", "serverID"})
+	event.SetLabel(types.LabelSignature, types.SignatureMissing)
+	row, err := eventToRow(event)
+	c.Assert(err, check.IsNil)
+	c.Assert(row.insertID, check.Equals, event.Spec.ID)
+	c.Assert(row.values, check.DeepEquals, map[string]bigquery.Value{
+		"time":      event.Metadata.Created,
+		"signature": types.SignatureMissing,
+		"id":        event.Spec.ID,
+		"action":    event.Spec.Action,
+		"accountID": event.Spec.AccountID,
+		"serverID":  event.Spec.ServerID,
+	})
+	type spec2 struct {
+		Labels map[string]string `json:"labels"`
+		Counts []int64           `json:"counts,omitempty"`
+		Hidden string            `json:"-"`
+	}
+	schema, err = structSchema(reflect.TypeOf(spec2{}))
+	c.Assert(err, check.IsNil)
+	c.Assert(len(schema), check.Equals, 2)
+	c.Assert(schema[0].Type, check.Equals, bigquery.RecordFieldType)
+	c.Assert(schema[0].Repeated, check.Equals, true)
+	c.Assert(schema[1].Name, check.Equals, "counts")
+	c.Assert(schema[1].Type, check.Equals, bigquery.IntegerFieldType)
+	values := structValues(reflect.ValueOf(spec2{Labels: map[string]string{"b": "2", "a": "1"}}))
+	c.Assert(values["labels"], check.DeepEquals, []bigquery.Value{
+		map[string]bigquery.Value{"key": "a", "value": "1"},
+		map[string]bigquery.Value{"key": "b", "value": "2"},
+	})
+}
+
+// TestBQEmulator tests loading events into a local BigQuery emulator,
+// it is skipped unless the emulator endpoint is provided in environment
+func (r *ReportingSuite) TestBQEmulator(c *check.C) {
+	endpoint := os.Getenv(bqEmulatorEnv)
+	if endpoint == "" {
+		c.Skip(fmt.Sprintf("%v is not set", bqEmulatorEnv))
+	}
+	for _, mode := range []string{BigQueryWriteStreaming, BigQueryWriteLoad} {
+		sink, err := NewBigQuerySink(BigQueryConfig{
+			ProjectID:    "test",
+			Endpoint:     endpoint,
+			WriteMode:    mode,
+			TablePerKind: mode == BigQueryWriteLoad,
+		})
+		c.Assert(err, check.IsNil)
+		err = sink.Put([]types.Event{
+			types.NewServerLoginEvent(uuid.New().String()),
+			types.NewUserLoginEvent(uuid.New().String()),
+		})
+		c.Assert(err, check.IsNil)
+		c.Assert(sink.Close(), check.IsNil)
+	}
+}
+
+// TestRouter tests sending events to sinks based on match rules
+func (r *ReportingSuite) TestRouter(c *check.C) {
+	allCh := make(chan types.Event, 10)
+	trialCh := make(chan types.Event, 10)
+	router, err := NewRouterSink(RouterConfig{
+		Routes: []Route{
+			{Sink: NewChannelSink(allCh)},
+			{
+				Match: Match{
+					Names:      []string{types.EventTypeUser},
+					Actions:    []string{types.EventActionLogin},
+					AccountIDs: []string{"trial-*"},
+				},
+				Sink: NewChannelSink(trialCh),
+			},
+		},
+	})
+	c.Assert(err, check.IsNil)
+	trialUser := types.NewUserLoginEvent(uuid.New().String())
+	trialUser.SetAccountID("trial-1")
+	paidUser := types.NewUserLoginEvent(uuid.New().String())
+	paidUser.SetAccountID("paid-1")
+	trialServer := types.NewServerLoginEvent(uuid.New().String())
+	trialServer.SetAccountID("trial-1")
+	events := []types.Event{trialUser, paidUser, trialServer}
+	c.Assert(router.Put(events), check.IsNil)
+	c.Assert(len(allCh), check.Equals, 3)
+	c.Assert(len(trialCh), check.Equals, 1)
+	c.Assert(<-trialCh, check.Equals, trialUser)
+	// sampling decision is stable for the same event
+	match := Match{SamplePercent: SamplePercent(50)}
+	for _, event := range events {
+		c.Assert(match.Matches(event), check.Equals, match.Matches(event))
+	}
+	// zero percent drops all events while all percent keeps them
+	none := Match{SamplePercent: SamplePercent(0)}
+	all := Match{SamplePercent: SamplePercent(100)}
+	for _, event := range events {
+		c.Assert(none.Matches(event), check.Equals, false)
+		c.Assert(all.Matches(event), check.Equals, true)
+	}
+	var config RouterConfig
+	err = json.Unmarshal([]byte(`{"routes": [{"match": {"names": ["user"], "samplePercent": 0}}]}`), &config)
+	c.Assert(err, check.IsNil)
+	c.Assert(config.Routes, check.HasLen, 1)
+	c.Assert(*config.Routes[0].Match.SamplePercent, check.Equals, float64(0))
+	_, err = NewRouterSink(RouterConfig{
+		Routes: []Route{{Match: Match{Names: []string{"["}}, Sink: NewLogSink()}},
+	})
+	c.Assert(err, check.NotNil)
+	_, err = NewRouterSink(RouterConfig{
+		Routes: []Route{{Match: Match{SamplePercent: SamplePercent(101)}, Sink: NewLogSink()}},
+	})
+	c.Assert(err, check.NotNil)
 }
 
 // startTestServer starts gRPC events server that will be submitting events
 // into the provided channel, and returns the server address
 func startTestServer(c *check.C, ch chan types.Event) (addr string) {
+	return startTestGRPCServer(c, newTestServer(c, ServerConfig{
+		Sinks: []Sink{NewChannelSink(ch)},
+	}))
+}
+
+// newTestServer returns a new reporting server with the provided config
+func newTestServer(c *check.C, config ServerConfig) *server {
+	server, err := NewServerWithConfig(config)
+	c.Assert(err, check.IsNil)
+	return server
+}
+
+// startTestGRPCServer starts gRPC server with the provided events service
+// and returns the server address
+func startTestGRPCServer(c *check.C, service reporting.EventsServiceServer) (addr string) {
 	// generate certificate authority
 	ca, err := authority.GenerateSelfSignedCA(csr.CertificateRequest{CN: "localhost"})
 	c.Assert(err, check.IsNil)
@@ -94,9 +928,7 @@ func startTestServer(c *check.C, ch chan types.Event) (addr string) {
 	l, err := net.Listen("tcp", "localhost:0")
 	c.Assert(err, check.IsNil)
 	server := grpc.NewServer(grpc.Creds(credentials.NewServerTLSFromCert(&cert)))
-	reporting.RegisterEventsServiceServer(server, NewServer(ServerConfig{
-		Sinks: []Sink{NewChannelSink(ch)},
-	}))
+	reporting.RegisterEventsServiceServer(server, service)
 	go server.Serve(l)
 	return l.Addr().String()
 }
@@ -111,5 +943,10 @@ func getTestClient(c *check.C, addr string) rclient.Client {
 	return client
 }
 
-// testTimeout is how long to wait for events during tests
-const testTimeout = 5 * time.Second
+const (
+	// testTimeout is how long to wait for events during tests
+	testTimeout = 5 * time.Second
+	// bqEmulatorEnv is the environment variable with the endpoint of
+	// a local BigQuery emulator, e.g. http://localhost:9050
+	bqEmulatorEnv = "REPORTING_BIGQUERY_EMULATOR"
+)
@@ -0,0 +1,171 @@
+/*
+Copyright 2017 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"hash/fnv"
+	"path"
+
+	"github.com/gravitational/reporting/types"
+
+	"github.com/gravitational/trace"
+)
+
+// RouterConfig is config for the router sink
+type RouterConfig struct {
+	// Routes is the list of routes, each event is sent to the sinks of
+	// all routes that match it
+	Routes []Route `json:"routes"`
+}
+
+// Route sends events that match its rules to a sink
+type Route struct {
+	// Match defines which events are sent to the sink
+	Match Match `json:"match"`
+	// Sink is the sink that receives matching events
+	Sink Sink `json:"-"`
+}
+
+// Match defines declarative event matching rules. Each list of patterns
+// matches if it is empty or any of the patterns matches, and an event
+// matches if all of the lists match. Patterns use shell glob syntax, e.g.
+// "trial-*", see path.Match for details
+type Match struct {
+	// Names are the event name patterns, such as "user"
+	Names []string `json:"names,omitempty"`
+	// Actions are the event action patterns, such as "login"
+	Actions []string `json:"actions,omitempty"`
+	// AccountIDs are the event account ID patterns
+	AccountIDs []string `json:"accountIDs,omitempty"`
+	// SamplePercent is the optional percentage of matching events to keep,
+	// from 0 to 100, all events are kept if it is not set. Sampling is based
+	// on event ID so the same event is always either kept or dropped
+	SamplePercent *float64 `json:"samplePercent,omitempty"`
+}
+
+// Check makes sure that match rules are valid
+func (m Match) Check() error {
+	for _, patterns := range [][]string{m.Names, m.Actions, m.AccountIDs} {
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return trace.BadParameter("invalid pattern %q: %v", pattern, err)
+			}
+		}
+	}
+	if m.SamplePercent != nil && (*m.SamplePercent < 0 || *m.SamplePercent > 100) {
+		return trace.BadParameter("sample percent should be between 0 and 100, got %v",
+			*m.SamplePercent)
+	}
+	return nil
+}
+
+// Matches returns true if the provided event matches the rules
+func (m Match) Matches(event types.Event) bool {
+	if !matchAny(m.Names, event.GetName()) {
+		return false
+	}
+	if !matchAny(m.Actions, event.GetAction()) {
+		return false
+	}
+	if !matchAny(m.AccountIDs, event.GetAccountID()) {
+		return false
+	}
+	if m.SamplePercent == nil {
+		return true
+	}
+	hash := fnv.New32a()
+	hash.Write([]byte(event.GetID()))
+	return float64(hash.Sum32()%samplePrecision) < *m.SamplePercent*samplePrecision/100
+}
+
+// SamplePercent returns a pointer to the provided sample percentage
+func SamplePercent(percent float64) *float64 {
+	return &percent
+}
+
+// Filter returns events that match the rules
+func (m Match) Filter(events []types.Event) []types.Event {
+	var matched []types.Event
+	for _, event := range events {
+		if m.Matches(event) {
+			matched = append(matched, event)
+		}
+	}
+	return matched
+}
+
+// matchAny returns true if the list of patterns is empty or any of
+// the patterns matches the provided value
+func matchAny(patterns []string, value string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, value); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Check makes sure that router sink config is valid
+func (c RouterConfig) Check() error {
+	if len(c.Routes) == 0 {
+		return trace.BadParameter("router config is missing routes")
+	}
+	for i, route := range c.Routes {
+		if route.Sink == nil {
+			return trace.BadParameter("route %v is missing sink", i)
+		}
+		if err := route.Match.Check(); err != nil {
+			return trace.Wrap(err, "route %v", i)
+		}
+	}
+	return nil
+}
+
+// NewRouterSink returns a new sink that sends events to child sinks based
+// on the configured match rules
+func NewRouterSink(config RouterConfig) (*routerSink, error) {
+	if err := config.Check(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &routerSink{RouterConfig: config}, nil
+}
+
+type routerSink struct {
+	RouterConfig
+}
+
+// Put sends each of the provided events to the sinks of matching routes
+func (s *routerSink) Put(events []types.Event) error {
+	var errors []error
+	for _, route := range s.Routes {
+		matched := route.Match.Filter(events)
+		if len(matched) == 0 {
+			continue
+		}
+		if err := route.Sink.Put(matched); err != nil {
+			errors = append(errors, trace.Wrap(err))
+		}
+	}
+	return trace.NewAggregate(errors...)
+}
+
+// samplePrecision is the number of buckets events are hashed into when
+// sampling, allows sampling percentages with two decimal places
+const samplePrecision = 10000
@@ -0,0 +1,479 @@
+/*
+Copyright 2017 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gravitational/reporting/types"
+
+	"github.com/google/uuid"
+	"github.com/gravitational/trace"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	log "github.com/sirupsen/logrus"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// S3Config is config for S3-compatible object storage sink
+type S3Config struct {
+	// Endpoint is the object storage endpoint, e.g. s3.amazonaws.com
+	Endpoint string `json:"endpoint"`
+	// Bucket is the bucket files are written to
+	Bucket string `json:"bucket"`
+	// Prefix is an optional prefix of the written files
+	Prefix string `json:"prefix,omitempty"`
+	// Region is the bucket region
+	Region string `json:"region,omitempty"`
+	// AccessKeyID is the access key ID used for authentication
+	AccessKeyID string `json:"accessKeyID"`
+	// SecretAccessKey is the secret access key used for authentication
+	SecretAccessKey string `json:"secretAccessKey"`
+	// Insecure is whether to connect to the endpoint over plain HTTP
+	Insecure bool `json:"insecure,omitempty"`
+	// FlushInterval is how often buffered events are written
+	FlushInterval time.Duration `json:"flushInterval,omitempty"`
+	// FlushCount is the number of buffered events that triggers an early write
+	FlushCount int `json:"flushCount,omitempty"`
+}
+
+// CheckAndSetDefaults makes sure that S3 sink config is valid and sets
+// defaults for the unspecified fields
+func (c *S3Config) CheckAndSetDefaults() error {
+	if c.Endpoint == "" {
+		return trace.BadParameter("s3 config is missing endpoint")
+	}
+	if c.Bucket == "" {
+		return trace.BadParameter("s3 config is missing bucket")
+	}
+	if c.FlushInterval == 0 {
+		c.FlushInterval = s3FlushInterval
+	}
+	if c.FlushCount == 0 {
+		c.FlushCount = s3FlushCount
+	}
+	return nil
+}
+
+// objectUploader is the part of the object storage client used by the sink,
+// it may be substituted with an in-process stand-in in tests
+type objectUploader interface {
+	// PutObject uploads an object
+	PutObject(ctx context.Context, bucket, key string, reader io.Reader, size int64, opts minio.PutObjectOptions) (minio.UploadInfo, error)
+}
+
+// NewS3Sink returns a new sink that buffers events and periodically writes
+// them as Parquet files into an S3-compatible bucket. Files are partitioned
+// by event kind and date, e.g. "kind=user/date=2017-10-17/<id>.parquet"
+func NewS3Sink(config S3Config) (*s3Sink, error) {
+	if err := config.CheckAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	client, err := minio.New(config.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(config.AccessKeyID, config.SecretAccessKey, ""),
+		Secure: !config.Insecure,
+		Region: config.Region,
+	})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return newS3Sink(config, client), nil
+}
+
+// newS3Sink returns a new S3 sink that uses the provided uploader
+func newS3Sink(config S3Config, uploader objectUploader) *s3Sink {
+	ctx, cancel := context.WithCancel(context.Background())
+	sink := &s3Sink{
+		S3Config:   config,
+		uploader:   uploader,
+		partitions: make(map[s3Partition][]types.Event),
+		ctx:        ctx,
+		cancel:     cancel,
+		doneCh:     make(chan struct{}),
+		flushCh:    make(chan struct{}, 1),
+	}
+	go sink.flushPeriodically()
+	return sink
+}
+
+type s3Sink struct {
+	S3Config
+	uploader objectUploader
+	// mu protects the buffered events
+	mu sync.Mutex
+	// partitions are the buffered events grouped by destination partition
+	partitions map[s3Partition][]types.Event
+	// count is the number of buffered events
+	count int
+	// ctx is used to stop the flush goroutine
+	ctx    context.Context
+	cancel context.CancelFunc
+	// doneCh is closed when the flush goroutine has exited
+	doneCh chan struct{}
+	// flushCh signals the flush goroutine to write the buffer early
+	flushCh chan struct{}
+	// closeErr is the error of the final flush, it is set before doneCh
+	// is closed
+	closeErr error
+}
+
+// s3Partition identifies files events are written to
+type s3Partition struct {
+	// kind is the event name
+	kind string
+	// date is the event creation date
+	date string
+}
+
+// Put buffers the provided events, they are written to the bucket
+// periodically or once enough events have been accumulated, it never
+// waits for uploads
+func (s *s3Sink) Put(events []types.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.count+len(events) > s.maxBufferedEvents() {
+		return trace.LimitExceeded("s3 sink buffer is full, %v events are waiting to be written",
+			s.count)
+	}
+	for _, event := range events {
+		partition := s3Partition{
+			kind: event.GetName(),
+			date: event.GetMetadata().Created.UTC().Format(s3DateFormat),
+		}
+		s.partitions[partition] = append(s.partitions[partition], event)
+	}
+	s.count += len(events)
+	if s.count >= s.FlushCount {
+		select {
+		case s.flushCh <- struct{}{}:
+		default: // flush has already been requested
+		}
+	}
+	return nil
+}
+
+// Close writes events that have been buffered so far and stops the sink,
+// it returns an error if buffered events could not be written
+func (s *s3Sink) Close() error {
+	s.cancel()
+	<-s.doneCh
+	return trace.Wrap(s.closeErr)
+}
+
+// maxBufferedEvents returns the number of buffered events after which
+// the sink starts rejecting new events until the buffer has been written
+func (s *s3Sink) maxBufferedEvents() int {
+	return s3MaxBufferedBatches * s.FlushCount
+}
+
+// flushPeriodically writes buffered events until the sink is closed
+func (s *s3Sink) flushPeriodically() {
+	defer close(s.doneCh)
+	ticker := time.NewTicker(s.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-s.flushCh:
+		case <-s.ctx.Done():
+			s.closeErr = s.flush()
+			if s.closeErr != nil {
+				log.Errorf("Failed to write events to S3 on shutdown: %v.", trace.DebugReport(s.closeErr))
+			}
+			return
+		}
+		if err := s.flush(); err != nil {
+			log.Warnf("Failed to write events to S3: %v.", trace.DebugReport(err))
+		}
+	}
+}
+
+// flush writes a file for each buffered partition, the lock is not held
+// while files are uploaded so recording events is not blocked, partitions
+// that failed to be uploaded are put back into the buffer while partitions
+// that failed to be encoded are dropped as they would fail again
+func (s *s3Sink) flush() error {
+	s.mu.Lock()
+	partitions := s.partitions
+	s.partitions = make(map[s3Partition][]types.Event)
+	s.mu.Unlock()
+	var errors []error
+	for partition, events := range partitions {
+		var data bytes.Buffer
+		if err := eventsToParquet(events, &data); err != nil {
+			log.Errorf("Dropping %v %v events that failed to encode: %v.",
+				len(events), partition.kind, err)
+			errors = append(errors, trace.Wrap(err))
+			s.mu.Lock()
+			s.count -= len(events)
+			s.mu.Unlock()
+			continue
+		}
+		err := s.upload(partition, &data, len(events))
+		s.mu.Lock()
+		if err != nil {
+			errors = append(errors, trace.Wrap(err))
+			s.partitions[partition] = append(events, s.partitions[partition]...)
+		} else {
+			s.count -= len(events)
+		}
+		s.mu.Unlock()
+	}
+	return trace.NewAggregate(errors...)
+}
+
+// upload uploads the encoded events to a new Parquet file in the partition
+func (s *s3Sink) upload(partition s3Partition, data *bytes.Buffer, count int) error {
+	key := s.objectKey(partition)
+	ctx, cancel := context.WithTimeout(context.Background(), s3UploadTimeout)
+	defer cancel()
+	_, err := s.uploader.PutObject(ctx, s.Bucket, key, data, int64(data.Len()),
+		minio.PutObjectOptions{ContentType: s3ContentType})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	log.Debugf("wrote %v events to %v", count, key)
+	return nil
+}
+
+// objectKey returns a new unique key of a file in the partition
+func (s *s3Sink) objectKey(partition s3Partition) string {
+	return path.Join(s.Prefix,
+		fmt.Sprintf("kind=%v", invalidTableChars.ReplaceAllString(partition.kind, "_")),
+		fmt.Sprintf("date=%v", partition.date),
+		fmt.Sprintf("%v-%v.parquet", time.Now().UTC().Format(s3TimeFormat), uuid.New()))
+}
+
+// eventsToParquet writes the provided events of the same kind as a Parquet
+// file with the schema derived from the event spec
+func eventsToParquet(events []types.Event, w io.Writer) error {
+	if len(events) == 0 {
+		return nil
+	}
+	spec, err := eventSpec(events[0])
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	schema, err := parquetSchema(spec.Type())
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	pw, err := writer.NewJSONWriterFromWriter(schema, w, s3WriterParallelism)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	pw.CompressionType = parquet.CompressionCodec_SNAPPY
+	for _, event := range events {
+		spec, err := eventSpec(event)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		row := parquetValue(spec).(map[string]interface{})
+		row[parquetTimeColumn] = parquetTimestamp(event.GetMetadata().Created)
+		row[parquetSignatureColumn] = signatureStatus(event)
+		data, err := json.Marshal(row)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		if err := pw.Write(string(data)); err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	if err := pw.WriteStop(); err != nil {
+		return trace.Wrap(err)
+	}
+	return nil
+}
+
+// parquetSchema returns the Parquet JSON schema of files with events of
+// the provided spec type: the event timestamp and signature status followed
+// by all spec fields
+func parquetSchema(specType reflect.Type) (string, error) {
+	fields, err := parquetStructFields(specType)
+	if err != nil {
+		return "", trace.Wrap(err)
+	}
+	root := parquetField{
+		Tag: "name=parquet_go_root, repetitiontype=REQUIRED",
+		Fields: append([]parquetField{{
+			Tag: fmt.Sprintf("name=%v, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=REQUIRED",
+				parquetTimeColumn),
+		}, {
+			Tag: fmt.Sprintf("name=%v, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=REQUIRED",
+				parquetSignatureColumn),
+		}}, fields...),
+	}
+	data, err := json.Marshal(root)
+	if err != nil {
+		return "", trace.Wrap(err)
+	}
+	return string(data), nil
+}
+
+// parquetField is a field of the Parquet JSON schema
+type parquetField struct {
+	// Tag describes the field name, type and repetition
+	Tag string `json:"Tag"`
+	// Fields are the nested fields of groups, lists and maps
+	Fields []parquetField `json:"Fields,omitempty"`
+}
+
+// parquetStructFields returns Parquet schema fields of the provided struct
+// type named after their JSON names
+func parquetStructFields(structType reflect.Type) ([]parquetField, error) {
+	var fields []parquetField
+	for _, field := range jsonFields(structType) {
+		f, err := parquetTypeField(field.name, field.Type, "OPTIONAL")
+		if err != nil {
+			return nil, trace.Wrap(err, "field %v.%v", structType.Name(), field.Name)
+		}
+		fields = append(fields, *f)
+	}
+	return fields, nil
+}
+
+// parquetTypeField returns Parquet schema field of the provided type
+func parquetTypeField(name string, fieldType reflect.Type, repetition string) (*parquetField, error) {
+	tag := func(attributes ...string) string {
+		return strings.Join(append(append([]string{"name=" + name}, attributes...),
+			"repetitiontype="+repetition), ", ")
+	}
+	if fieldType == timeType {
+		return &parquetField{Tag: tag("type=INT64", "convertedtype=TIMESTAMP_MILLIS")}, nil
+	}
+	switch fieldType.Kind() {
+	case reflect.String:
+		return &parquetField{Tag: tag("type=BYTE_ARRAY", "convertedtype=UTF8")}, nil
+	case reflect.Bool:
+		return &parquetField{Tag: tag("type=BOOLEAN")}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &parquetField{Tag: tag("type=INT64")}, nil
+	case reflect.Float32, reflect.Float64:
+		return &parquetField{Tag: tag("type=DOUBLE")}, nil
+	case reflect.Ptr:
+		return parquetTypeField(name, fieldType.Elem(), repetition)
+	case reflect.Struct:
+		fields, err := parquetStructFields(fieldType)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		return &parquetField{Tag: tag(), Fields: fields}, nil
+	case reflect.Slice, reflect.Array:
+		if fieldType.Elem().Kind() == reflect.Uint8 {
+			return &parquetField{Tag: tag("type=BYTE_ARRAY")}, nil
+		}
+		element, err := parquetTypeField("element", fieldType.Elem(), "REQUIRED")
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		return &parquetField{Tag: tag("type=LIST"), Fields: []parquetField{*element}}, nil
+	case reflect.Map:
+		if fieldType.Key().Kind() != reflect.String {
+			return nil, trace.BadParameter("only maps with string keys are supported")
+		}
+		value, err := parquetTypeField("value", fieldType.Elem(), "OPTIONAL")
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		return &parquetField{Tag: tag("type=MAP"), Fields: []parquetField{
+			{Tag: "name=key, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=REQUIRED"},
+			*value,
+		}}, nil
+	}
+	return nil, trace.BadParameter("unsupported field type %v", fieldType)
+}
+
+// parquetValue converts the provided value to its JSON representation
+// matching the Parquet schema
+func parquetValue(value reflect.Value) interface{} {
+	if value.Type() == timeType {
+		return parquetTimestamp(value.Interface().(time.Time))
+	}
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+			return nil
+		}
+		return parquetValue(value.Elem())
+	case reflect.Struct:
+		values := make(map[string]interface{})
+		for _, field := range jsonFields(value.Type()) {
+			values[field.name] = parquetValue(value.FieldByIndex(field.Index))
+		}
+		return values
+	case reflect.Slice, reflect.Array:
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			return value.Interface()
+		}
+		values := make([]interface{}, value.Len())
+		for i := 0; i < value.Len(); i++ {
+			values[i] = parquetValue(value.Index(i))
+		}
+		return values
+	case reflect.Map:
+		keys := value.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		values := make(map[string]interface{}, len(keys))
+		for _, key := range keys {
+			values[key.String()] = parquetValue(value.MapIndex(key))
+		}
+		return values
+	}
+	return value.Interface()
+}
+
+// parquetTimestamp returns the provided time as Parquet millisecond timestamp
+func parquetTimestamp(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+const (
+	// s3FlushInterval is how often buffered events are written by default
+	s3FlushInterval = 5 * time.Minute
+	// s3FlushCount is the default number of buffered events that triggers
+	// a write
+	s3FlushCount = 10000
+	// s3MaxBufferedBatches is the number of flush batches that can be
+	// buffered before the sink starts rejecting new events
+	s3MaxBufferedBatches = 10
+	// s3UploadTimeout is how long to wait for a single file upload
+	s3UploadTimeout = time.Minute
+	// s3ContentType is the content type of the written files
+	s3ContentType = "application/vnd.apache.parquet"
+	// s3DateFormat is the format of the date partition
+	s3DateFormat = "2006-01-02"
+	// s3TimeFormat is the format of the timestamp in the file names
+	s3TimeFormat = "20060102T150405Z"
+	// s3WriterParallelism is the number of goroutines used to encode files
+	s3WriterParallelism = 4
+	// parquetTimeColumn is the name of the event timestamp column
+	parquetTimeColumn = "time"
+	// parquetSignatureColumn is the name of the event signature status column
+	parquetSignatureColumn = "signature"
+)
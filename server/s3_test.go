@@ -0,0 +1,244 @@
+/*
+Copyright 2017 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gravitational/reporting/types"
+
+	"github.com/google/uuid"
+	"github.com/gravitational/trace"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	check "gopkg.in/check.v1"
+)
+
+type S3Suite struct{}
+
+var _ = check.Suite(&S3Suite{})
+
+// TestS3 tests writing partitioned files to object storage
+func (s *S3Suite) TestS3(c *check.C) {
+	uploader := &testUploader{objects: make(map[string][]byte)}
+	sink := newS3Sink(S3Config{
+		Bucket:        "events",
+		Prefix:        "reporting",
+		FlushInterval: time.Hour,
+		FlushCount:    3,
+	}, uploader)
+	yesterday := types.NewUserLoginEvent(uuid.New().String())
+	yesterday.Metadata.Created = time.Date(2017, 10, 16, 12, 0, 0, 0, time.UTC)
+	today := types.NewUserLoginEvent(uuid.New().String())
+	today.Metadata.Created = time.Date(2017, 10, 17, 12, 0, 0, 0, time.UTC)
+	server := types.NewServerLoginEvent(uuid.New().String())
+	server.Metadata.Created = time.Date(2017, 10, 17, 13, 0, 0, 0, time.UTC)
+	c.Assert(sink.Put([]types.Event{yesterday, today}), check.IsNil)
+	c.Assert(uploader.keys(), check.HasLen, 0)
+	c.Assert(sink.Put([]types.Event{server}), check.IsNil)
+	keys := uploader.waitForKeys(c, 3)
+	var prefixes []string
+	for _, key := range keys {
+		c.Assert(strings.HasSuffix(key, ".parquet"), check.Equals, true)
+		prefixes = append(prefixes, key[:strings.LastIndex(key, "/")])
+		c.Assert(strings.HasPrefix(string(uploader.objects[key]), "PAR1"), check.Equals, true)
+	}
+	c.Assert(prefixes, check.DeepEquals, []string{
+		"reporting/kind=server/date=2017-10-17",
+		"reporting/kind=user/date=2017-10-16",
+		"reporting/kind=user/date=2017-10-17",
+	})
+	// batches that do not fit into the buffer are rejected
+	err := sink.Put(make([]types.Event, sink.maxBufferedEvents()+1))
+	c.Assert(trace.IsLimitExceeded(err), check.Equals, true)
+	// buffered events are written on close
+	c.Assert(sink.Put([]types.Event{types.NewUserLoginEvent(uuid.New().String())}), check.IsNil)
+	c.Assert(uploader.keys(), check.HasLen, 3)
+	c.Assert(sink.Close(), check.IsNil)
+	c.Assert(uploader.keys(), check.HasLen, 4)
+}
+
+// TestS3Errors tests that events that failed to upload are kept in the
+// buffer while events that failed to encode are dropped
+func (s *S3Suite) TestS3Errors(c *check.C) {
+	uploader := &testUploader{objects: make(map[string][]byte)}
+	sink := newS3Sink(S3Config{
+		Bucket:        "events",
+		FlushInterval: time.Hour,
+		FlushCount:    100,
+	}, uploader)
+	invalid := unlabeledEvent{types.NewServerLoginEvent(uuid.New().String())}
+	event := types.NewUserLoginEvent(uuid.New().String())
+	c.Assert(sink.Put([]types.Event{event, invalid}), check.IsNil)
+	uploader.setError(trace.ConnectionProblem(nil, "connection refused"))
+	c.Assert(sink.flush(), check.NotNil)
+	c.Assert(sink.count, check.Equals, 1)
+	// failed uploads are returned on close
+	c.Assert(sink.Close(), check.NotNil)
+	c.Assert(uploader.keys(), check.HasLen, 0)
+}
+
+// TestParquetSchema tests deriving Parquet schema from event specs
+func (s *S3Suite) TestParquetSchema(c *check.C) {
+	schema, err := parquetSchema(reflect.TypeOf(types.UserEventSpec{}))
+	c.Assert(err, check.IsNil)
+	var root parquetField
+	c.Assert(json.Unmarshal([]byte(schema), &root), check.IsNil)
+	c.Assert(root.Fields, check.DeepEquals, []parquetField{
+		{Tag: "name=time, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=REQUIRED"},
+		{Tag: "name=signature, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=REQUIRED"},
+		{Tag: "name=id, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"},
+		{Tag: "name=action, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"},
+		{Tag: "name=accountID, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"},
+		{Tag: "name=userID, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"},
+	})
+	type spec struct {
+		Labels map[string]string `json:"labels"`
+		Counts []int64           `json:"counts"`
+	}
+	fields, err := parquetStructFields(reflect.TypeOf(spec{}))
+	c.Assert(err, check.IsNil)
+	c.Assert(fields, check.DeepEquals, []parquetField{
+		{
+			Tag: "name=labels, type=MAP, repetitiontype=OPTIONAL",
+			Fields: []parquetField{
+				{Tag: "name=key, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=REQUIRED"},
+				{Tag: "name=value, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"},
+			},
+		},
+		{
+			Tag: "name=counts, type=LIST, repetitiontype=OPTIONAL",
+			Fields: []parquetField{
+				{Tag: "name=element, type=INT64, repetitiontype=REQUIRED"},
+			},
+		},
+	})
+}
+
+// TestS3MinIO tests writing files to a local MinIO server, it is skipped
+// unless the server endpoint is provided in environment
+func (s *S3Suite) TestS3MinIO(c *check.C) {
+	endpoint := os.Getenv(s3EndpointEnv)
+	if endpoint == "" {
+		c.Skip(fmt.Sprintf("%v is not set", s3EndpointEnv))
+	}
+	config := S3Config{
+		Endpoint:        endpoint,
+		Bucket:          "reporting-test",
+		Prefix:          uuid.New().String(),
+		AccessKeyID:     "minioadmin",
+		SecretAccessKey: "minioadmin",
+		Insecure:        true,
+	}
+	client, err := minio.New(config.Endpoint, &minio.Options{
+		Creds: credentials.NewStaticV4(config.AccessKeyID, config.SecretAccessKey, ""),
+	})
+	c.Assert(err, check.IsNil)
+	ctx := context.Background()
+	exists, err := client.BucketExists(ctx, config.Bucket)
+	c.Assert(err, check.IsNil)
+	if !exists {
+		c.Assert(client.MakeBucket(ctx, config.Bucket, minio.MakeBucketOptions{}), check.IsNil)
+	}
+	sink, err := NewS3Sink(config)
+	c.Assert(err, check.IsNil)
+	err = sink.Put([]types.Event{
+		types.NewServerLoginEvent(uuid.New().String()),
+		types.NewUserLoginEvent(uuid.New().String()),
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(sink.Close(), check.IsNil)
+	var keys []string
+	for object := range client.ListObjects(ctx, config.Bucket, minio.ListObjectsOptions{
+		Prefix:    config.Prefix + "/",
+		Recursive: true,
+	}) {
+		c.Assert(object.Err, check.IsNil)
+		keys = append(keys, object.Key)
+	}
+	c.Assert(keys, check.HasLen, 2)
+}
+
+// testUploader is an in-process object storage stand-in
+type testUploader struct {
+	sync.Mutex
+	// objects maps keys to uploaded objects
+	objects map[string][]byte
+	// err is returned from uploads when set
+	err error
+}
+
+// PutObject saves the object in memory
+func (u *testUploader) PutObject(ctx context.Context, bucket, key string, reader io.Reader, size int64, opts minio.PutObjectOptions) (minio.UploadInfo, error) {
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return minio.UploadInfo{}, err
+	}
+	u.Lock()
+	defer u.Unlock()
+	if u.err != nil {
+		return minio.UploadInfo{}, u.err
+	}
+	u.objects[key] = data
+	return minio.UploadInfo{Bucket: bucket, Key: key, Size: size}, nil
+}
+
+// setError makes subsequent uploads fail with the provided error
+func (u *testUploader) setError(err error) {
+	u.Lock()
+	defer u.Unlock()
+	u.err = err
+}
+
+// waitForKeys waits until the provided number of objects has been uploaded
+// and returns their sorted keys
+func (u *testUploader) waitForKeys(c *check.C, count int) []string {
+	for start := time.Now(); time.Since(start) < testTimeout; time.Sleep(10 * time.Millisecond) {
+		if keys := u.keys(); len(keys) >= count {
+			c.Assert(keys, check.HasLen, count)
+			return keys
+		}
+	}
+	c.Fatalf("timeout waiting for %v objects, got %v", count, len(u.keys()))
+	return nil
+}
+
+// keys returns sorted keys of the uploaded objects
+func (u *testUploader) keys() []string {
+	u.Lock()
+	defer u.Unlock()
+	var keys []string
+	for key := range u.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// s3EndpointEnv is the environment variable with the endpoint of a local
+// MinIO server with default credentials, e.g. localhost:9000
+const s3EndpointEnv = "REPORTING_S3_ENDPOINT"
@@ -17,45 +17,187 @@ limitations under the License.
 package server
 
 import (
+	"strings"
+
 	"github.com/gravitational/reporting"
 	"github.com/gravitational/reporting/types"
 
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/gravitational/trace"
+	"github.com/gravitational/trace/trail"
 	log "github.com/sirupsen/logrus"
 	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 )
 
 // ServerConfig defines the reporting server config
 type ServerConfig struct {
 	// Sinks is the list of event sinks
 	Sinks []Sink
+	// Validation defines the rules recorded events are validated with,
+	// unset rules use default values
+	Validation types.ValidationConfig
+	// Verifier is the optional event signature verification config,
+	// event signatures are not verified if it is not set
+	Verifier *VerifierConfig
+	// Tail is the optional sink to stream recorded events from, recorded
+	// events are published to it directly so it must not be one of the sinks
+	Tail Subscriber
+	// Admins is the list of common names of client certificates that
+	// are allowed to tail events, the gRPC server must be configured to
+	// require and verify client certificates
+	Admins []string
+}
+
+// CheckAndSetDefaults validates the config and sets default values
+func (c *ServerConfig) CheckAndSetDefaults() error {
+	if err := c.Validation.CheckAndSetDefaults(); err != nil {
+		return trace.Wrap(err)
+	}
+	if c.Verifier != nil {
+		if err := c.Verifier.Check(); err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	if c.Tail != nil {
+		for _, sink := range c.Sinks {
+			if sink == Sink(c.Tail) {
+				return trace.BadParameter("tail sink must not be one of the sinks")
+			}
+		}
+	}
+	return nil
 }
 
-// NewServer returns a new reporting gRPC server
+// NewServer returns a new reporting gRPC server, it panics if the config
+// is invalid, use NewServerWithConfig to handle invalid configs
 func NewServer(config ServerConfig) *server {
+	server, err := NewServerWithConfig(config)
+	if err != nil {
+		panic(trace.DebugReport(err))
+	}
+	return server
+}
+
+// NewServerWithConfig returns a new reporting gRPC server after checking
+// the provided config
+func NewServerWithConfig(config ServerConfig) (*server, error) {
+	if err := config.CheckAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
 	return &server{
 		ServerConfig: config,
-	}
+	}, nil
 }
 
 type server struct {
 	ServerConfig
 }
 
-// Record accepts events over gRPC and saves them in the configured sinks
+// Record accepts events over gRPC and saves them in the configured sinks,
+// invalid events are dropped so that they do not fail the rest of the batch
 func (s *server) Record(ctx context.Context, grpcEvents *reporting.GRPCEvents) (*empty.Empty, error) {
-	events, err := types.FromGRPCEvents(*grpcEvents)
-	if err != nil {
-		log.Errorf(trace.DebugReport(err))
-		return nil, trace.Wrap(err)
+	events := make([]types.Event, 0, len(grpcEvents.Events))
+	for _, grpcEvent := range grpcEvents.Events {
+		event, err := s.convertEvent(*grpcEvent)
+		if err != nil {
+			// the client can send the batch again in an older version
+			if types.IsVersionError(err) {
+				return nil, rejectVersion(ctx, err)
+			}
+			log.Warnf("Dropping invalid event: %v.", err)
+			continue
+		}
+		events = append(events, event)
+	}
+	if len(events) == 0 {
+		return &empty.Empty{}, nil
+	}
+	// events are streamed even if saving them fails below
+	if s.Tail != nil {
+		if err := s.Tail.Put(events); err != nil {
+			log.Warnf("Failed to publish events to subscribers: %v.", trace.DebugReport(err))
+		}
 	}
 	for _, sink := range s.Sinks {
 		err := sink.Put(events)
 		if err != nil {
 			log.Error(trace.DebugReport(err))
-			return nil, trace.Wrap(err)
+			return nil, trail.ToGRPC(err)
 		}
 	}
 	return &empty.Empty{}, nil
 }
+
+// rejectVersion returns the gRPC error for a batch with events of an
+// unsupported resource version, the supported versions are sent in the
+// trailer so that clients can tell it from other invalid batches
+func rejectVersion(ctx context.Context, err error) error {
+	trailer := metadata.Pairs(types.SupportedVersionsTrailer,
+		strings.Join(types.SupportedVersions, ","))
+	if err := grpc.SetTrailer(ctx, trailer); err != nil {
+		log.Debugf("Failed to set trailer: %v.", err)
+	}
+	return trail.ToGRPC(err)
+}
+
+// convertEvent converts gRPC event to event, validates it and verifies
+// its signature if verification is configured
+func (s *server) convertEvent(grpcEvent reporting.GRPCEvent) (types.Event, error) {
+	event, err := types.DecodeGRPCEvent(grpcEvent)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if err := types.ValidateEvent(event, s.Validation); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	// signature status is only set by servers, otherwise clients could
+	// claim their events have been verified
+	if _, ok := event.GetMetadata().Labels[types.LabelSignature]; ok {
+		return nil, trace.BadParameter("event must not carry the %q label", types.LabelSignature)
+	}
+	if s.Verifier != nil {
+		if err := verifyEvent(*s.Verifier, grpcEvent, event); err != nil {
+			return nil, trace.Wrap(err)
+		}
+	}
+	return event, nil
+}
+
+// TailEvents streams events matching the filter as they are recorded
+func (s *server) TailEvents(grpcFilter *reporting.EventFilter, stream reporting.EventsService_TailEventsServer) error {
+	if err := authorizeAdmin(stream.Context(), s.Admins); err != nil {
+		return trace.Wrap(err)
+	}
+	if s.Tail == nil {
+		return trace.NotImplemented("tailing events is not configured")
+	}
+	sub, err := s.Tail.Subscribe(fromGRPCFilter(grpcFilter))
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	defer func() {
+		sub.Close()
+		if dropped := sub.Dropped(); dropped != 0 {
+			log.Warnf("Dropped %v events for slow tail subscriber.", dropped)
+		}
+	}()
+	for {
+		select {
+		case event, ok := <-sub.Events():
+			if !ok {
+				return nil
+			}
+			grpcEvent, err := types.ToGRPCEvent(event)
+			if err != nil {
+				return trace.Wrap(err)
+			}
+			if err := stream.Send(grpcEvent); err != nil {
+				return trace.Wrap(err)
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
@@ -17,8 +17,15 @@ limitations under the License.
 package server
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gravitational/reporting/types"
@@ -26,6 +33,7 @@ import (
 	"cloud.google.com/go/bigquery"
 	"github.com/gravitational/trace"
 	log "github.com/sirupsen/logrus"
+	"google.golang.org/api/option"
 )
 
 // Sink defines an event sink interface
@@ -74,6 +82,22 @@ type BigQueryConfig struct {
 	// be setup as described in
 	// https://cloud.google.com/docs/authentication/getting-started
 	ProjectID string `json:"projectID"`
+	// WriteMode is how events are written into BigQuery: "streaming" (the
+	// default) inserts each batch using the streaming API, "load" buffers
+	// events and periodically commits them using batch load jobs
+	WriteMode string `json:"writeMode,omitempty"`
+	// LoadInterval is how often buffered events are committed in "load" mode
+	LoadInterval time.Duration `json:"loadInterval,omitempty"`
+	// LoadBatchSize is the number of buffered events that triggers an early
+	// commit in "load" mode
+	LoadBatchSize int `json:"loadBatchSize,omitempty"`
+	// Endpoint is an optional BigQuery API endpoint, for example of a local
+	// BigQuery emulator, in which case authentication is disabled
+	Endpoint string `json:"endpoint,omitempty"`
+	// TablePerKind is whether each event kind is saved into its own table
+	// with schema derived from the event spec, instead of the shared
+	// events table
+	TablePerKind bool `json:"tablePerKind,omitempty"`
 }
 
 // Check makes sure that BigQuery sink config is valid
@@ -81,39 +105,140 @@ func (c BigQueryConfig) Check() error {
 	if c.ProjectID == "" {
 		return trace.BadParameter("bigquery config is missing project id")
 	}
+	switch c.WriteMode {
+	case "", BigQueryWriteStreaming, BigQueryWriteLoad:
+	default:
+		return trace.BadParameter("unsupported bigquery write mode %q, supported are: %q, %q",
+			c.WriteMode, BigQueryWriteStreaming, BigQueryWriteLoad)
+	}
+	return nil
+}
+
+// CheckAndSetDefaults makes sure that BigQuery sink config is valid and
+// sets defaults for the unspecified fields
+func (c *BigQueryConfig) CheckAndSetDefaults() error {
+	if err := c.Check(); err != nil {
+		return trace.Wrap(err)
+	}
+	if c.WriteMode == "" {
+		c.WriteMode = BigQueryWriteStreaming
+	}
+	if c.LoadInterval == 0 {
+		c.LoadInterval = bqLoadInterval
+	}
+	if c.LoadBatchSize == 0 {
+		c.LoadBatchSize = bqLoadBatchSize
+	}
 	return nil
 }
 
 // NewBigQuerySink returns a new Google BigQuery events sink
 func NewBigQuerySink(config BigQueryConfig) (*bigQuerySink, error) {
-	err := config.Check()
+	err := config.CheckAndSetDefaults()
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
-	client, err := bigquery.NewClient(context.Background(), config.ProjectID)
+	var options []option.ClientOption
+	if config.Endpoint != "" {
+		options = append(options,
+			option.WithEndpoint(config.Endpoint),
+			option.WithoutAuthentication())
+	}
+	client, err := bigquery.NewClient(context.Background(), config.ProjectID, options...)
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
-	bigQuery := &bigQuerySink{client: client}
+	ctx, cancel := context.WithCancel(context.Background())
+	bigQuery := &bigQuerySink{
+		BigQueryConfig: config,
+		client:         client,
+		ctx:            ctx,
+		cancel:         cancel,
+		doneCh:         make(chan struct{}),
+		loadCh:         make(chan struct{}, 1),
+		tables:         make(map[string]bigquery.Schema),
+	}
 	err = bigQuery.initSchema()
 	if err != nil {
+		cancel()
 		return nil, trace.Wrap(err)
 	}
+	if config.WriteMode == BigQueryWriteLoad {
+		go bigQuery.loadPeriodically()
+	} else {
+		close(bigQuery.doneCh)
+	}
 	return bigQuery, nil
 }
 
 type bigQuerySink struct {
+	BigQueryConfig
 	client *bigquery.Client
+	// mu protects the buffer of events waiting to be loaded
+	mu sync.Mutex
+	// buffer holds events accumulated in "load" mode
+	buffer []types.Event
+	// pendingRows is the number of rows in pending batches
+	pendingRows int
+	// pending are batches that have not been loaded yet, they are only
+	// accessed by the load goroutine and retried as is so that job IDs
+	// stay the same
+	pending []*bqBatch
+	// ctx is used to stop the load goroutine
+	ctx    context.Context
+	cancel context.CancelFunc
+	// doneCh is closed when the load goroutine has exited
+	doneCh chan struct{}
+	// loadCh signals the load goroutine to load the buffer early
+	loadCh chan struct{}
+	// closeErr is the error of the final load, it is set before doneCh
+	// is closed
+	closeErr error
+	// tablesMu protects the per-kind tables
+	tablesMu sync.Mutex
+	// tables maps event names to schemas of the per-kind tables that
+	// have been initialized
+	tables map[string]bigquery.Schema
 }
 
 // Put saves a series of events into Google BigQuery
 func (q *bigQuerySink) Put(events []types.Event) error {
-	uploader := q.client.Dataset(bqDatasetName).Table(bqTableName).Uploader()
+	if q.WriteMode == BigQueryWriteLoad {
+		return q.enqueue(events)
+	}
+	return q.insert(events)
+}
+
+// Close commits events that have been buffered so far and releases
+// resources held by the sink, it returns an error if buffered events
+// could not be loaded
+func (q *bigQuerySink) Close() error {
+	q.cancel()
+	<-q.doneCh
+	return trace.NewAggregate(q.closeErr, q.client.Close())
+}
+
+// insert inserts the provided events using the streaming API
+func (q *bigQuerySink) insert(events []types.Event) error {
+	batches, err := q.batches(events)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	var errors []error
+	for _, batch := range batches {
+		errors = append(errors, q.insertBatch(batch))
+	}
+	return trace.NewAggregate(errors...)
+}
+
+// insertBatch inserts the provided batch of rows using the streaming API
+func (q *bigQuerySink) insertBatch(batch *bqBatch) error {
+	uploader := q.client.Dataset(bqDatasetName).Table(batch.table).Uploader()
 	// in case of persistent error the call will run indefinitely so
 	// pass a context with timeout to prevent hanging calls
 	ctx, cancel := context.WithTimeout(context.Background(), bqUploadTimeout)
 	defer cancel() // release resources if operation completed before timeout
-	err := uploader.Put(ctx, eventsToStructSavers(events))
+	err := uploader.Put(ctx, batch.savers)
 	if err != nil {
 		if pme, ok := err.(bigquery.PutMultiError); ok {
 			var errors []error
@@ -127,6 +252,262 @@ func (q *bigQuerySink) Put(events []types.Event) error {
 	return nil
 }
 
+// batches groups the provided events by their destination table
+func (q *bigQuerySink) batches(events []types.Event) ([]*bqBatch, error) {
+	if !q.TablePerKind {
+		batch := &bqBatch{table: bqTableName, schema: tableSchema}
+		for _, event := range events {
+			saver, err := eventToStructSaver(event)
+			if err != nil {
+				log.Warn(trace.DebugReport(err))
+				continue
+			}
+			batch.add(saver, saver.InsertID, saver.Struct)
+		}
+		return []*bqBatch{batch}, nil
+	}
+	var batches []*bqBatch
+	byName := make(map[string]*bqBatch)
+	for _, event := range events {
+		batch, ok := byName[event.GetName()]
+		if !ok {
+			table, schema, err := q.kindTable(event)
+			if err != nil {
+				return nil, trace.Wrap(err)
+			}
+			batch = &bqBatch{table: table, schema: schema}
+			byName[event.GetName()] = batch
+			batches = append(batches, batch)
+		}
+		row, err := eventToRow(event)
+		if err != nil {
+			log.Warn(trace.DebugReport(err))
+			continue
+		}
+		batch.add(row, row.insertID, row.values)
+	}
+	return batches, nil
+}
+
+// enqueue adds the provided events to the buffer and signals the load
+// goroutine if the buffer has grown beyond the configured batch size, it
+// never waits for load jobs
+func (q *bigQuerySink) enqueue(events []types.Event) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.buffer)+q.pendingRows+len(events) > q.maxBufferedEvents() {
+		return trace.LimitExceeded("bigquery sink buffer is full, %v events are waiting to be loaded",
+			len(q.buffer)+q.pendingRows)
+	}
+	q.buffer = append(q.buffer, events...)
+	if len(q.buffer) >= q.LoadBatchSize {
+		select {
+		case q.loadCh <- struct{}{}:
+		default: // load has already been requested
+		}
+	}
+	return nil
+}
+
+// maxBufferedEvents returns the number of buffered events after which the
+// sink starts rejecting new events until the buffer has been loaded
+func (q *bigQuerySink) maxBufferedEvents() int {
+	return bqMaxBufferedBatches * q.LoadBatchSize
+}
+
+// loadPeriodically commits buffered events with load jobs until the sink
+// is closed
+func (q *bigQuerySink) loadPeriodically() {
+	defer close(q.doneCh)
+	ticker := time.NewTicker(q.LoadInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-q.loadCh:
+		case <-q.ctx.Done():
+			q.closeErr = q.loadBuffer()
+			if q.closeErr != nil {
+				log.Errorf("Failed to load events into BigQuery on shutdown: %v.", trace.DebugReport(q.closeErr))
+			}
+			return
+		}
+		if err := q.loadBuffer(); err != nil {
+			log.Warnf("Failed to load events into BigQuery: %v.", trace.DebugReport(err))
+		}
+	}
+}
+
+// loadBuffer groups buffered events into batches and commits pending
+// batches with load jobs, the lock is not held while jobs are running so
+// recording events is not blocked. Each batch is removed once it has been
+// loaded, batches that failed to load are retried on the next cycle unless
+// the failure is permanent, e.g. rows that do not match the table schema
+func (q *bigQuerySink) loadBuffer() error {
+	q.mu.Lock()
+	events := q.buffer
+	q.buffer = nil
+	q.pendingRows += len(events)
+	q.mu.Unlock()
+	if len(events) != 0 {
+		batches, err := q.batches(events)
+		if err != nil {
+			q.mu.Lock()
+			q.buffer = append(events, q.buffer...)
+			q.pendingRows -= len(events)
+			q.mu.Unlock()
+			return trace.Wrap(err)
+		}
+		for _, batch := range batches {
+			if len(batch.rows) != 0 {
+				q.pending = append(q.pending, batch)
+			}
+		}
+	}
+	var errors []error
+	var pending []*bqBatch
+	var pendingRows int
+	for _, batch := range q.pending {
+		if err := q.load(batch); err != nil {
+			errors = append(errors, trace.Wrap(err))
+			if trace.IsBadParameter(err) {
+				log.Errorf("Dropping %v rows for table %q that failed to load: %v.",
+					len(batch.rows), batch.table, err)
+				continue
+			}
+			pending = append(pending, batch)
+			pendingRows += len(batch.rows)
+		}
+	}
+	q.pending = pending
+	q.mu.Lock()
+	q.pendingRows = pendingRows
+	q.mu.Unlock()
+	return trace.NewAggregate(errors...)
+}
+
+// load commits the provided batch of rows using a load job with a newline
+// delimited JSON source, it returns a bad parameter error if the batch
+// can never be loaded
+func (q *bigQuerySink) load(batch *bqBatch) error {
+	if len(batch.rows) == 0 {
+		return nil // nothing to load
+	}
+	var data bytes.Buffer
+	if err := rowsToNDJSON(batch.rows, &data); err != nil {
+		return trace.BadParameter("failed to encode rows: %v", err)
+	}
+	source := bigquery.NewReaderSource(&data)
+	source.SourceFormat = bigquery.JSON
+	source.Schema = batch.schema
+	loader := q.client.Dataset(bqDatasetName).Table(batch.table).LoaderFrom(source)
+	loader.WriteDisposition = bigquery.WriteAppend
+	// job ID is derived from the batch contents so a batch that has been
+	// retried after a failed attempt (e.g. timeout waiting for the job)
+	// is not loaded twice because BigQuery rejects duplicate job IDs
+	loader.JobID = batch.jobID()
+	ctx, cancel := context.WithTimeout(context.Background(), bqLoadTimeout)
+	defer cancel()
+	job, err := loader.Run(ctx)
+	if err != nil {
+		if !strings.Contains(err.Error(), "Already Exists") {
+			return trace.Wrap(err)
+		}
+		// the batch has been submitted before, it has only been loaded
+		// if that job has succeeded
+		log.Debugf("load job %q already exists", loader.JobID)
+		job, err = q.client.JobFromID(ctx, loader.JobID)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	status, err := job.Wait(ctx)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if err := status.Err(); err != nil {
+		// the job has failed so the retry needs a new job
+		batch.attempt++
+		if !isRetryableJobError(err) {
+			return trace.BadParameter("load job %q failed: %v", loader.JobID, err)
+		}
+		return trace.Wrap(err)
+	}
+	log.Debugf("loaded %v rows into BigQuery table %q with job %q",
+		len(batch.rows), batch.table, loader.JobID)
+	return nil
+}
+
+// isRetryableJobError returns true if the load job failed with a transient
+// error and may succeed when the batch is loaded again
+func isRetryableJobError(err error) bool {
+	bqErr, ok := err.(*bigquery.Error)
+	if !ok {
+		return true
+	}
+	switch bqErr.Reason {
+	case "backendError", "internalError", "rateLimitExceeded", "timeout":
+		return true
+	}
+	return false
+}
+
+// bqBatch is a batch of rows destined to a single BigQuery table
+type bqBatch struct {
+	// table is the destination table name
+	table string
+	// schema is the destination table schema
+	schema bigquery.Schema
+	// savers are the rows in the format accepted by the streaming API
+	savers []bigquery.ValueSaver
+	// insertIDs are the row IDs used for de-duplication
+	insertIDs []string
+	// rows are the rows in the format accepted by load jobs
+	rows []interface{}
+	// attempt is the number of failed load jobs of the batch
+	attempt int
+}
+
+// jobID returns the ID of the batch load job, it only changes after a
+// load job of the batch has failed
+func (b *bqBatch) jobID() string {
+	id := loadJobID(b.table, b.insertIDs)
+	if b.attempt == 0 {
+		return id
+	}
+	return fmt.Sprintf("%v_%v", id, b.attempt)
+}
+
+// add adds a single row to the batch
+func (b *bqBatch) add(saver bigquery.ValueSaver, insertID string, row interface{}) {
+	b.savers = append(b.savers, saver)
+	b.insertIDs = append(b.insertIDs, insertID)
+	b.rows = append(b.rows, row)
+}
+
+// loadJobID returns a load job ID that is unique for the provided batch
+// of rows
+func loadJobID(table string, insertIDs []string) string {
+	hash := sha256.New()
+	io.WriteString(hash, table)
+	for _, id := range insertIDs {
+		io.WriteString(hash, id)
+	}
+	return fmt.Sprintf("%v%x", bqLoadJobPrefix, hash.Sum(nil))
+}
+
+// rowsToNDJSON writes provided rows in the newline delimited JSON format
+// accepted by BigQuery load jobs
+func rowsToNDJSON(rows []interface{}, w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	for _, row := range rows {
+		if err := encoder.Encode(row); err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	return nil
+}
+
 // initSchema initializes the dataset and table in Google BigQuery
 func (q *bigQuerySink) initSchema() error {
 	dataset := q.client.Dataset(bqDatasetName)
@@ -137,6 +518,9 @@ func (q *bigQuerySink) initSchema() error {
 		}
 		log.Debugf("dataset %q already exists", bqDatasetName)
 	}
+	if q.TablePerKind {
+		return nil // per-kind tables are initialized on first use
+	}
 	table := dataset.Table(bqTableName)
 	err = table.Create(context.Background(), &bigquery.TableMetadata{
 		Schema: tableSchema,
@@ -146,10 +530,60 @@ func (q *bigQuerySink) initSchema() error {
 			return trace.Wrap(err)
 		}
 		log.Debugf("table %q already exists", bqTableName)
+		if err := updateTableSchema(context.Background(), table, tableSchema); err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	return q.createSnapshotView(context.Background(), bqTableName,
+		fmt.Sprintf("type = '%v'", types.EventTypeSnapshot))
+}
+
+// createSnapshotView creates the view with the latest snapshot of each
+// account selected from the provided table with the optional condition,
+// the query of an existing view is updated as it may select from the
+// table of the other mode
+func (q *bigQuerySink) createSnapshotView(ctx context.Context, table, condition string) error {
+	if condition != "" {
+		condition = "WHERE " + condition
+	}
+	query := fmt.Sprintf(snapshotViewTemplate, q.ProjectID, bqDatasetName, table, condition)
+	view := q.client.Dataset(bqDatasetName).Table(bqSnapshotViewName)
+	err := view.Create(ctx, &bigquery.TableMetadata{
+		ViewQuery:      query,
+		UseStandardSQL: true,
+	})
+	if err == nil {
+		return nil
+	}
+	if !strings.Contains(err.Error(), "Already Exists") {
+		return trace.Wrap(err)
+	}
+	log.Debugf("view %q already exists", bqSnapshotViewName)
+	metadata, err := view.Metadata(ctx)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if metadata.ViewQuery == query {
+		return nil
+	}
+	_, err = view.Update(ctx, bigquery.TableMetadataToUpdate{ViewQuery: query}, metadata.ETag)
+	if err != nil {
+		return trace.Wrap(err)
 	}
+	log.Infof("updated view %q to select from table %q", bqSnapshotViewName, table)
 	return nil
 }
 
+// snapshotViewTemplate is the query of the view with the latest snapshot
+// of each account
+const snapshotViewTemplate = `SELECT * EXCEPT(snapshot_rank)
+FROM (
+  SELECT *, ROW_NUMBER() OVER (PARTITION BY accountID ORDER BY time DESC) AS snapshot_rank
+  FROM ` + "`%v.%v.%v`" + `
+  %v
+)
+WHERE snapshot_rank = 1`
+
 // eventsToStructSavers converts a slice of events into the format accepted by
 // the BigQuery client with proper schema
 func eventsToStructSavers(events []types.Event) []*bigquery.StructSaver {
@@ -177,7 +611,8 @@ func eventToStructSaver(event types.Event) (*bigquery.StructSaver, error) {
 				Action:    e.Spec.Action,
 				AccountID: e.Spec.AccountID,
 				ServerID:  e.Spec.ServerID,
-				Time:      e.GetMetadata().Created.Unix(),
+				Time:      e.GetMetadata().Created.Truncate(time.Second),
+				Signature: signatureStatus(e),
 			},
 		}, nil
 	case *types.UserEvent:
@@ -189,7 +624,64 @@ func eventToStructSaver(event types.Event) (*bigquery.StructSaver, error) {
 				Action:    e.Spec.Action,
 				AccountID: e.Spec.AccountID,
 				UserID:    e.Spec.UserID,
-				Time:      e.GetMetadata().Created.Unix(),
+				Time:      e.GetMetadata().Created.Truncate(time.Second),
+				Signature: signatureStatus(e),
+			},
+		}, nil
+	case *types.ActionEvent:
+		return &bigquery.StructSaver{
+			Schema:   tableSchema,
+			InsertID: e.Spec.ID,
+			Struct: bqActionEvent{
+				Type:        e.GetName(),
+				Action:      e.Spec.Action,
+				AccountID:   e.Spec.AccountID,
+				SubjectType: e.Spec.SubjectType,
+				SubjectID:   e.Spec.SubjectID,
+				Labels:      labelsToBQ(e.Spec.Labels),
+				Time:        e.GetMetadata().Created.Truncate(time.Second),
+				Signature:   signatureStatus(e),
+			},
+		}, nil
+	case *types.UsageEvent:
+		return &bigquery.StructSaver{
+			Schema:   tableSchema,
+			InsertID: e.Spec.ID,
+			Struct: bqUsageEvent{
+				Type:        e.GetName(),
+				AccountID:   e.Spec.AccountID,
+				Metric:      e.Spec.Metric,
+				Quantity:    e.Spec.Quantity,
+				Unit:        e.Spec.Unit,
+				PeriodStart: e.Spec.PeriodStart,
+				PeriodEnd:   e.Spec.PeriodEnd,
+				Time:        e.GetMetadata().Created.Truncate(time.Second),
+				Signature:   signatureStatus(e),
+			},
+		}, nil
+	case *types.SnapshotEvent:
+		return &bigquery.StructSaver{
+			Schema:   tableSchema,
+			InsertID: e.Spec.ID,
+			Struct: bqSnapshotEvent{
+				Type:           e.GetName(),
+				AccountID:      e.Spec.AccountID,
+				ProductVersion: e.Spec.ProductVersion,
+				Gauges:         gaugesToBQ(e.Spec.Gauges),
+				Time:           e.GetMetadata().Created.Truncate(time.Second),
+				Signature:      signatureStatus(e),
+			},
+		}, nil
+	case *types.ConsentEvent:
+		return &bigquery.StructSaver{
+			Schema:   tableSchema,
+			InsertID: e.Spec.ID,
+			Struct: bqConsentEvent{
+				Type:      e.GetName(),
+				Action:    e.Spec.Action,
+				AccountID: e.Spec.AccountID,
+				Time:      e.GetMetadata().Created.Truncate(time.Second),
+				Signature: signatureStatus(e),
 			},
 		}, nil
 	default:
@@ -197,32 +689,156 @@ func eventToStructSaver(event types.Event) (*bigquery.StructSaver, error) {
 	}
 }
 
+// gaugesToBQ converts gauges to BigQuery key/value records sorted by key
+func gaugesToBQ(gauges map[string]int64) []bqGauge {
+	keys := make([]string, 0, len(gauges))
+	for key := range gauges {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	result := make([]bqGauge, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, bqGauge{Key: key, Value: gauges[key]})
+	}
+	return result
+}
+
+// labelsToBQ converts labels to BigQuery key/value records sorted by key
+func labelsToBQ(labels map[string]string) []bqLabel {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	result := make([]bqLabel, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, bqLabel{Key: key, Value: labels[key]})
+	}
+	return result
+}
+
 // bqServerEvents represents BigQuery server event schema
 type bqServerEvent struct {
 	// Type is the event type
-	Type string
+	Type string `json:"type"`
 	// Action is the event action
-	Action string
+	Action string `json:"action"`
 	// AccountID is ID of account that triggered the event
-	AccountID string
+	AccountID string `json:"accountID"`
 	// ServerID is ID of server that triggered the event
-	ServerID string
+	ServerID string `json:"serverID"`
 	// Time is the event timestamp
-	Time int64
+	Time time.Time `json:"time"`
+	// Signature is the event signature verification status
+	Signature string `json:"signature"`
+}
+
+// bqConsentEvent represents BigQuery consent event schema
+type bqConsentEvent struct {
+	// Type is the event type
+	Type string `json:"type"`
+	// Action is the consent change
+	Action string `json:"action"`
+	// AccountID is ID of account that changed consent
+	AccountID string `json:"accountID"`
+	// Time is the event timestamp
+	Time time.Time `json:"time"`
+	// Signature is the event signature verification status
+	Signature string `json:"signature"`
 }
 
 // bqUserEvent represents BigQuery user event schema
 type bqUserEvent struct {
 	// Type is the event type
-	Type string
+	Type string `json:"type"`
 	// Action is the event action
-	Action string
+	Action string `json:"action"`
 	// AccountID is ID of account that triggered the event
-	AccountID string
+	AccountID string `json:"accountID"`
 	// UserID is ID of user that triggered the event
-	UserID string
+	UserID string `json:"userID"`
+	// Time is the event timestamp
+	Time time.Time `json:"time"`
+	// Signature is the event signature verification status
+	Signature string `json:"signature"`
+}
+
+// bqActionEvent represents BigQuery action event schema
+type bqActionEvent struct {
+	// Type is the event type
+	Type string `json:"type"`
+	// Action is the event action
+	Action string `json:"action"`
+	// AccountID is ID of account that triggered the event
+	AccountID string `json:"accountID"`
+	// SubjectType is the type of subject that performed the action
+	SubjectType string `json:"subjectType"`
+	// SubjectID is ID of subject that performed the action
+	SubjectID string `json:"subjectID"`
+	// Labels are the action labels
+	Labels []bqLabel `json:"labels"`
 	// Time is the event timestamp
-	Time int64
+	Time time.Time `json:"time"`
+	// Signature is the event signature verification status
+	Signature string `json:"signature"`
+}
+
+// bqUsageEvent represents BigQuery usage event schema
+type bqUsageEvent struct {
+	// Type is the event type
+	Type string `json:"type"`
+	// Action is the event action, always empty for usage events
+	Action string `json:"action"`
+	// AccountID is ID of account that consumed the resource
+	AccountID string `json:"accountID"`
+	// Metric is the metered metric name
+	Metric string `json:"metric"`
+	// Quantity is the decimal consumed amount
+	Quantity string `json:"quantity"`
+	// Unit is the quantity unit
+	Unit string `json:"unit"`
+	// PeriodStart is the start of the aggregation period
+	PeriodStart time.Time `json:"periodStart"`
+	// PeriodEnd is the end of the aggregation period
+	PeriodEnd time.Time `json:"periodEnd"`
+	// Time is the event timestamp
+	Time time.Time `json:"time"`
+	// Signature is the event signature verification status
+	Signature string `json:"signature"`
+}
+
+// bqSnapshotEvent represents BigQuery snapshot event schema
+type bqSnapshotEvent struct {
+	// Type is the event type
+	Type string `json:"type"`
+	// Action is the event action, always empty for snapshot events
+	Action string `json:"action"`
+	// AccountID is ID of account the snapshot was taken for
+	AccountID string `json:"accountID"`
+	// ProductVersion is the reporting product version
+	ProductVersion string `json:"productVersion"`
+	// Gauges are the snapshot gauges
+	Gauges []bqGauge `json:"gauges"`
+	// Time is the event timestamp
+	Time time.Time `json:"time"`
+	// Signature is the event signature verification status
+	Signature string `json:"signature"`
+}
+
+// bqGauge represents a single BigQuery snapshot gauge record
+type bqGauge struct {
+	// Key is the gauge name
+	Key string `json:"key"`
+	// Value is the gauge value
+	Value int64 `json:"value"`
+}
+
+// bqLabel represents a single BigQuery label record
+type bqLabel struct {
+	// Key is the label key
+	Key string `json:"key"`
+	// Value is the label value
+	Value string `json:"value"`
 }
 
 // tableSchema describes BigQuery events table schema
@@ -247,6 +863,10 @@ var tableSchema = bigquery.Schema{
 		Required: true,
 		Type:     bigquery.TimestampFieldType,
 	},
+	{
+		Name: bqSignatureColumn,
+		Type: bigquery.StringFieldType,
+	},
 	{
 		Name: "serverID",
 		Type: bigquery.StringFieldType,
@@ -255,6 +875,56 @@ var tableSchema = bigquery.Schema{
 		Name: "userID",
 		Type: bigquery.StringFieldType,
 	},
+	{
+		Name: "subjectType",
+		Type: bigquery.StringFieldType,
+	},
+	{
+		Name: "subjectID",
+		Type: bigquery.StringFieldType,
+	},
+	{
+		Name:     "labels",
+		Repeated: true,
+		Type:     bigquery.RecordFieldType,
+		Schema: bigquery.Schema{
+			{Name: bqMapKeyColumn, Required: true, Type: bigquery.StringFieldType},
+			{Name: bqMapValueColumn, Type: bigquery.StringFieldType},
+		},
+	},
+	{
+		Name: "metric",
+		Type: bigquery.StringFieldType,
+	},
+	{
+		Name: "quantity",
+		Type: bigquery.NumericFieldType,
+	},
+	{
+		Name: "unit",
+		Type: bigquery.StringFieldType,
+	},
+	{
+		Name: "periodStart",
+		Type: bigquery.TimestampFieldType,
+	},
+	{
+		Name: "periodEnd",
+		Type: bigquery.TimestampFieldType,
+	},
+	{
+		Name: "productVersion",
+		Type: bigquery.StringFieldType,
+	},
+	{
+		Name:     "gauges",
+		Repeated: true,
+		Type:     bigquery.RecordFieldType,
+		Schema: bigquery.Schema{
+			{Name: bqMapKeyColumn, Required: true, Type: bigquery.StringFieldType},
+			{Name: bqMapValueColumn, Type: bigquery.IntegerFieldType},
+		},
+	},
 }
 
 const (
@@ -262,6 +932,30 @@ const (
 	bqDatasetName = "houston"
 	// bqTableName is the BigQuery events table name
 	bqTableName = "events"
+	// bqSnapshotViewName is the BigQuery view with the latest snapshot
+	// of each account
+	bqSnapshotViewName = "latest_snapshots"
 	// bqUploadTimeout is how long the upload method should retry in case of failures
 	bqUploadTimeout = 10 * time.Second
+	// bqLoadTimeout is how long to wait for a load job to complete
+	bqLoadTimeout = time.Minute
+	// bqLoadInterval is how often buffered events are loaded by default
+	bqLoadInterval = 5 * time.Minute
+	// bqLoadBatchSize is the default number of buffered events that
+	// triggers a load
+	bqLoadBatchSize = 10000
+	// bqMaxBufferedBatches is the number of load batches that can be
+	// buffered before the sink starts rejecting new events
+	bqMaxBufferedBatches = 10
+	// bqLoadJobPrefix is the prefix of load job IDs
+	bqLoadJobPrefix = "reporting_load_"
+)
+
+const (
+	// BigQueryWriteStreaming is the write mode that inserts events
+	// using the BigQuery streaming API
+	BigQueryWriteStreaming = "streaming"
+	// BigQueryWriteLoad is the write mode that buffers events and commits
+	// them using BigQuery batch load jobs
+	BigQueryWriteLoad = "load"
 )
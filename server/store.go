@@ -0,0 +1,408 @@
+/*
+Copyright 2017 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"os"
+	"time"
+
+	"github.com/gravitational/reporting"
+	"github.com/gravitational/reporting/types"
+
+	"github.com/gravitational/trace"
+	bolt "go.etcd.io/bbolt"
+)
+
+// StoreConfig is the embedded event store configuration
+type StoreConfig struct {
+	// Path is the path to the database file
+	Path string `json:"path"`
+	// OpenTimeout is how long to wait for the database file lock
+	OpenTimeout time.Duration `json:"openTimeout,omitempty"`
+}
+
+// CheckAndSetDefaults validates the config and sets default values
+func (c *StoreConfig) CheckAndSetDefaults() error {
+	if c.Path == "" {
+		return trace.BadParameter("missing Path")
+	}
+	if c.OpenTimeout == 0 {
+		c.OpenTimeout = storeOpenTimeout
+	}
+	return nil
+}
+
+// EventFilter defines a query against the embedded event store
+type EventFilter struct {
+	// AccountID is the account ID to match, empty matches all accounts
+	AccountID string
+	// Name is the event name to match, empty matches all names
+	Name string
+	// Action is the event action to match, empty matches all actions
+	Action string
+	// From is the start of the time range, inclusive, zero means unbounded
+	From time.Time
+	// To is the end of the time range, exclusive, zero means unbounded
+	To time.Time
+	// Limit is the maximum number of events to return
+	Limit int
+	// Cursor resumes the query after the last event of a previous page
+	Cursor string
+}
+
+// CheckAndSetDefaults validates the filter and sets default values
+func (f *EventFilter) CheckAndSetDefaults() error {
+	if !f.From.IsZero() && !f.To.IsZero() && !f.From.Before(f.To) {
+		return trace.BadParameter("time range start %v is not before end %v", f.From, f.To)
+	}
+	if f.Limit < 0 || f.Limit > storeMaxQueryLimit {
+		return trace.BadParameter("limit should be between 0 and %v, got %v",
+			storeMaxQueryLimit, f.Limit)
+	}
+	if f.Limit == 0 {
+		f.Limit = storeQueryLimit
+	}
+	if f.Cursor != "" {
+		if _, err := decodeCursor(f.Cursor); err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	return nil
+}
+
+// matches returns true if the event matches the filter fields that are
+// not served by the index used for the query
+func (f EventFilter) matches(event types.Event) bool {
+	return (f.AccountID == "" || f.AccountID == event.GetAccountID()) &&
+		(f.Name == "" || f.Name == event.GetName()) &&
+		(f.Action == "" || f.Action == event.GetAction())
+}
+
+// QueryResult is a single page of events returned by the store
+type QueryResult struct {
+	// Events is the list of matching events ordered by creation time
+	Events []types.Event
+	// Cursor is the cursor of the next page, empty if there are no more events
+	Cursor string
+}
+
+// NewStoreSink returns a new sink that saves events in an embedded
+// database indexed by account, name, action and creation time
+func NewStoreSink(config StoreConfig) (*storeSink, error) {
+	if err := config.CheckAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	db, err := bolt.Open(config.Path, os.FileMode(0600), &bolt.Options{
+		Timeout: config.OpenTimeout,
+	})
+	if err != nil {
+		return nil, trace.ConvertSystemError(err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range storeBuckets {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return trace.Wrap(err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, trace.Wrap(err)
+	}
+	return &storeSink{
+		StoreConfig: config,
+		db:          db,
+	}, nil
+}
+
+type storeSink struct {
+	StoreConfig
+	db *bolt.DB
+}
+
+// Put saves the provided events in the store, events that have already
+// been saved are ignored
+func (s *storeSink) Put(events []types.Event) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		ids := tx.Bucket(storeIDsBucket)
+		for _, event := range events {
+			if ids.Get([]byte(event.GetID())) != nil {
+				continue // duplicate
+			}
+			grpcEvent, err := types.ToGRPCEvent(event)
+			if err != nil {
+				return trace.Wrap(err)
+			}
+			key := eventKey(event)
+			if err := tx.Bucket(storeEventsBucket).Put(key, grpcEvent.Data); err != nil {
+				return trace.Wrap(err)
+			}
+			if err := ids.Put([]byte(event.GetID()), key); err != nil {
+				return trace.Wrap(err)
+			}
+			for _, index := range storeIndexes {
+				err := tx.Bucket(index.bucket).Put(indexKey(index.value(event), key), nil)
+				if err != nil {
+					return trace.Wrap(err)
+				}
+			}
+			if event.GetName() == types.EventTypeSnapshot {
+				if err := upsertSnapshot(tx, event, key); err != nil {
+					return trace.Wrap(err)
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// LatestSnapshot returns the most recent snapshot of the provided account
+func (s *storeSink) LatestSnapshot(accountID string) (*types.SnapshotEvent, error) {
+	var snapshot *types.SnapshotEvent
+	err := s.db.View(func(tx *bolt.Tx) error {
+		key := tx.Bucket(storeSnapshotsBucket).Get([]byte(accountID))
+		if key == nil {
+			return trace.NotFound("no snapshots for account %q", accountID)
+		}
+		event, err := types.DecodeGRPCEvent(reporting.GRPCEvent{
+			Data: tx.Bucket(storeEventsBucket).Get(key),
+		})
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		var ok bool
+		if snapshot, ok = event.(*types.SnapshotEvent); !ok {
+			return trace.BadParameter("expected snapshot event, got %T", event)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return snapshot, nil
+}
+
+// upsertSnapshot makes the snapshot event with the provided key the latest
+// snapshot of its account unless a more recent snapshot has been saved
+func upsertSnapshot(tx *bolt.Tx, event types.Event, key []byte) error {
+	snapshots := tx.Bucket(storeSnapshotsBucket)
+	latest := snapshots.Get([]byte(event.GetAccountID()))
+	if latest != nil && bytes.Compare(latest, key) > 0 {
+		return nil // snapshots may arrive out of order
+	}
+	return trace.Wrap(snapshots.Put([]byte(event.GetAccountID()), key))
+}
+
+// Query returns a page of events matching the provided filter
+func (s *storeSink) Query(filter EventFilter) (*QueryResult, error) {
+	if err := filter.CheckAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var result QueryResult
+	err := s.db.View(func(tx *bolt.Tx) error {
+		events := tx.Bucket(storeEventsBucket)
+		scanner := newStoreScanner(tx, filter)
+		for key := scanner.first(); key != nil; key = scanner.next() {
+			data := events.Get(key)
+			if data == nil {
+				return trace.NotFound("event %x is missing from the store", key)
+			}
+			event, err := types.DecodeGRPCEvent(reporting.GRPCEvent{Data: data})
+			if err != nil {
+				return trace.Wrap(err)
+			}
+			if !filter.matches(event) {
+				continue
+			}
+			if len(result.Events) == filter.Limit {
+				result.Cursor = encodeCursor(eventKey(result.Events[len(result.Events)-1]))
+				return nil
+			}
+			result.Events = append(result.Events, event)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &result, nil
+}
+
+// Close closes the underlying database
+func (s *storeSink) Close() error {
+	return trace.Wrap(s.db.Close())
+}
+
+// storeScanner iterates over event keys in creation time order using
+// the most selective index available for the filter
+type storeScanner struct {
+	cursor *bolt.Cursor
+	// prefix is the index prefix of the scanned keys
+	prefix []byte
+	// start is the first key to scan
+	start []byte
+	// end is the key to stop the scan at, exclusive
+	end []byte
+	// after is the key of the last event returned on the previous page
+	after []byte
+}
+
+// newStoreScanner returns a scanner for the provided filter
+func newStoreScanner(tx *bolt.Tx, filter EventFilter) *storeScanner {
+	scanner := &storeScanner{cursor: tx.Bucket(storeEventsBucket).Cursor()}
+	for _, index := range storeIndexes {
+		if value := index.filterValue(filter); value != "" {
+			scanner.cursor = tx.Bucket(index.bucket).Cursor()
+			scanner.prefix = indexKey(value, nil)
+			break
+		}
+	}
+	scanner.start = append(append([]byte{}, scanner.prefix...), timeKey(filter.From)...)
+	if !filter.To.IsZero() {
+		scanner.end = append(append([]byte{}, scanner.prefix...), timeKey(filter.To)...)
+	}
+	if filter.Cursor != "" {
+		after, _ := decodeCursor(filter.Cursor)
+		scanner.after = append(append([]byte{}, scanner.prefix...), after...)
+		if bytes.Compare(scanner.after, scanner.start) > 0 {
+			scanner.start = scanner.after
+		}
+	}
+	return scanner
+}
+
+// first returns the first event key in range, or nil
+func (s *storeScanner) first() []byte {
+	key, _ := s.cursor.Seek(s.start)
+	if key != nil && bytes.Equal(key, s.after) {
+		key, _ = s.cursor.Next()
+	}
+	return s.eventKey(key)
+}
+
+// next returns the next event key in range, or nil
+func (s *storeScanner) next() []byte {
+	key, _ := s.cursor.Next()
+	return s.eventKey(key)
+}
+
+// eventKey returns the event key for the provided scanned key, or nil
+// if the key is out of the scanned range
+func (s *storeScanner) eventKey(key []byte) []byte {
+	if key == nil || !bytes.HasPrefix(key, s.prefix) {
+		return nil
+	}
+	if s.end != nil && bytes.Compare(key, s.end) >= 0 {
+		return nil
+	}
+	return key[len(s.prefix):]
+}
+
+// storeIndex defines a secondary index on one of the event fields
+type storeIndex struct {
+	// bucket is the index bucket name
+	bucket []byte
+	// value returns the indexed field of the event
+	value func(types.Event) string
+	// filterValue returns the indexed field of the filter
+	filterValue func(EventFilter) string
+}
+
+// storeIndexes lists secondary indexes in the order of their selectivity
+var storeIndexes = []storeIndex{
+	{
+		bucket:      []byte("index_account"),
+		value:       func(e types.Event) string { return e.GetAccountID() },
+		filterValue: func(f EventFilter) string { return f.AccountID },
+	},
+	{
+		bucket:      []byte("index_action"),
+		value:       func(e types.Event) string { return e.GetAction() },
+		filterValue: func(f EventFilter) string { return f.Action },
+	},
+	{
+		bucket:      []byte("index_name"),
+		value:       func(e types.Event) string { return e.GetName() },
+		filterValue: func(f EventFilter) string { return f.Name },
+	},
+}
+
+// eventKey returns the primary key of the event which sorts events by
+// creation time
+func eventKey(event types.Event) []byte {
+	return append(timeKey(event.GetMetadata().Created), event.GetID()...)
+}
+
+// timeKey returns sortable binary representation of the provided time
+func timeKey(t time.Time) []byte {
+	key := make([]byte, 8)
+	if !t.IsZero() {
+		binary.BigEndian.PutUint64(key, uint64(t.UnixNano()))
+	}
+	return key
+}
+
+// indexKey returns the index key for the provided field value and event key
+func indexKey(value string, key []byte) []byte {
+	return append(append([]byte(value), 0), key...)
+}
+
+// encodeCursor returns the opaque cursor for the provided event key
+func encodeCursor(key []byte) string {
+	return base64.RawURLEncoding.EncodeToString(key)
+}
+
+// decodeCursor returns the event key from the provided opaque cursor
+func decodeCursor(cursor string) ([]byte, error) {
+	key, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(key) < 8 {
+		return nil, trace.BadParameter("invalid cursor %q", cursor)
+	}
+	return key, nil
+}
+
+var (
+	// storeEventsBucket is the bucket with events keyed by creation time
+	storeEventsBucket = []byte("events")
+	// storeIDsBucket is the bucket that maps event IDs to event keys
+	storeIDsBucket = []byte("ids")
+	// storeSnapshotsBucket is the bucket that maps account IDs to keys
+	// of their latest snapshot events
+	storeSnapshotsBucket = []byte("snapshots")
+	// storeBuckets lists all buckets of the store
+	storeBuckets = [][]byte{
+		storeEventsBucket,
+		storeIDsBucket,
+		storeSnapshotsBucket,
+		storeIndexes[0].bucket,
+		storeIndexes[1].bucket,
+		storeIndexes[2].bucket,
+	}
+)
+
+const (
+	// storeOpenTimeout is how long to wait for the database file lock
+	storeOpenTimeout = 5 * time.Second
+	// storeQueryLimit is the default number of events returned by a query
+	storeQueryLimit = 100
+	// storeMaxQueryLimit is the maximum number of events returned by a query
+	storeMaxQueryLimit = 1000
+)
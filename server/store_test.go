@@ -0,0 +1,210 @@
+/*
+Copyright 2017 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"github.com/gravitational/reporting"
+	"github.com/gravitational/reporting/types"
+
+	"github.com/google/uuid"
+	"github.com/gravitational/trace"
+	check "gopkg.in/check.v1"
+)
+
+type StoreSuite struct {
+	store *storeSink
+}
+
+var _ = check.Suite(&StoreSuite{})
+
+func (s *StoreSuite) SetUpTest(c *check.C) {
+	var err error
+	s.store, err = NewStoreSink(StoreConfig{
+		Path: filepath.Join(c.MkDir(), "events.db"),
+	})
+	c.Assert(err, check.IsNil)
+}
+
+func (s *StoreSuite) TearDownTest(c *check.C) {
+	c.Assert(s.store.Close(), check.IsNil)
+}
+
+// TestStoreQuery tests filtering stored events by fields and time range
+func (s *StoreSuite) TestStoreQuery(c *check.C) {
+	start := time.Date(2017, 10, 1, 0, 0, 0, 0, time.UTC)
+	var events []types.Event
+	for i := 0; i < 6; i++ {
+		var event types.Event
+		if i%2 == 0 {
+			event = types.NewUserLoginEvent(uuid.New().String())
+		} else {
+			event = types.NewServerLoginEvent(uuid.New().String())
+		}
+		event.SetAccountID([]string{"a", "b", "c"}[i%3])
+		setCreated(event, start.Add(time.Duration(i)*24*time.Hour))
+		events = append(events, event)
+	}
+	c.Assert(s.store.Put(events), check.IsNil)
+	// duplicates are ignored
+	c.Assert(s.store.Put(events[:2]), check.IsNil)
+
+	tests := []struct {
+		filter   EventFilter
+		expected []types.Event
+	}{
+		{filter: EventFilter{}, expected: events},
+		{filter: EventFilter{AccountID: "a"}, expected: []types.Event{events[0], events[3]}},
+		{filter: EventFilter{Name: types.EventTypeUser}, expected: []types.Event{events[0], events[2], events[4]}},
+		{filter: EventFilter{AccountID: "b", Name: types.EventTypeServer}, expected: []types.Event{events[1]}},
+		{filter: EventFilter{Action: "logout"}},
+		{
+			filter:   EventFilter{From: start.Add(24 * time.Hour), To: start.Add(3 * 24 * time.Hour)},
+			expected: []types.Event{events[1], events[2]},
+		},
+		{
+			filter:   EventFilter{Name: types.EventTypeUser, From: start.Add(time.Hour)},
+			expected: []types.Event{events[2], events[4]},
+		},
+	}
+	for _, test := range tests {
+		result, err := s.store.Query(test.filter)
+		c.Assert(err, check.IsNil)
+		c.Assert(result.Events, check.DeepEquals, test.expected, check.Commentf("%+v", test.filter))
+		c.Assert(result.Cursor, check.Equals, "")
+	}
+}
+
+// TestStorePagination tests paging through stored events with cursors
+func (s *StoreSuite) TestStorePagination(c *check.C) {
+	var events []types.Event
+	for i := 0; i < 5; i++ {
+		event := types.NewUserLoginEvent(uuid.New().String())
+		event.SetAccountID("a")
+		setCreated(event, event.Metadata.Created.Add(time.Duration(i)*time.Second))
+		events = append(events, event)
+	}
+	c.Assert(s.store.Put(events), check.IsNil)
+	for _, filter := range []EventFilter{{Limit: 2}, {AccountID: "a", Limit: 2}} {
+		var received []types.Event
+		for i := 0; i < 3; i++ {
+			result, err := s.store.Query(filter)
+			c.Assert(err, check.IsNil)
+			received = append(received, result.Events...)
+			filter.Cursor = result.Cursor
+		}
+		c.Assert(received, check.DeepEquals, events)
+		c.Assert(filter.Cursor, check.Equals, "")
+	}
+	_, err := s.store.Query(EventFilter{Cursor: "!"})
+	c.Assert(err, check.NotNil)
+	_, err = s.store.Query(EventFilter{Limit: storeMaxQueryLimit + 1})
+	c.Assert(err, check.NotNil)
+}
+
+// TestQueryServer tests listing and counting events over the query API
+func (s *StoreSuite) TestQueryServer(c *check.C) {
+	// 2017-10-02 is Monday
+	start := time.Date(2017, 10, 2, 12, 0, 0, 0, time.UTC)
+	var events []types.Event
+	for i, accountID := range []string{"a", "b", "a", "a"} {
+		event := types.NewUserLoginEvent(uuid.New().String())
+		event.SetAccountID(accountID)
+		setCreated(event, start.Add(time.Duration(i)*3*24*time.Hour))
+		events = append(events, event)
+	}
+	c.Assert(s.store.Put(events), check.IsNil)
+	server, err := NewQueryServer(QueryServerConfig{Sink: s.store, Admins: []string{"admin"}})
+	c.Assert(err, check.IsNil)
+	ctx := adminContext("admin")
+
+	list, err := server.ListEvents(ctx, &reporting.ListEventsRequest{
+		Filter: &reporting.EventFilter{AccountID: "a", From: start.Add(time.Hour).UnixNano()},
+		Limit:  1,
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(len(list.Events), check.Equals, 1)
+	c.Assert(list.Cursor, check.Not(check.Equals), "")
+	event, err := types.FromGRPCEvent(*list.Events[0])
+	c.Assert(err, check.IsNil)
+	c.Assert(event, check.DeepEquals, events[2])
+
+	day := func(d int) int64 { return time.Date(2017, 10, d, 0, 0, 0, 0, time.UTC).UnixNano() }
+	count, err := server.CountEvents(ctx, &reporting.CountEventsRequest{
+		GroupBy: reporting.GroupBy_ACCOUNT,
+		Period:  reporting.Period_WEEK,
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(count.Counts, check.DeepEquals, []*reporting.EventCount{
+		{Key: "a", Start: day(2), Count: 2},
+		{Key: "b", Start: day(2), Count: 1},
+		{Key: "a", Start: day(9), Count: 1},
+	})
+	count, err = server.CountEvents(ctx, &reporting.CountEventsRequest{
+		GroupBy: reporting.GroupBy_ACTION,
+		Period:  reporting.Period_MONTH,
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(count.Counts, check.DeepEquals, []*reporting.EventCount{
+		{Key: types.EventActionLogin, Start: day(1), Count: 4},
+	})
+
+	for _, ctx := range []context.Context{context.Background(), adminContext("user")} {
+		_, err = server.ListEvents(ctx, &reporting.ListEventsRequest{})
+		c.Assert(trace.IsAccessDenied(err), check.Equals, true)
+	}
+
+	_, err = NewQueryServer(QueryServerConfig{Sink: NewLogSink(), Admins: []string{"admin"}})
+	c.Assert(err, check.NotNil)
+}
+
+// TestStoreLatestSnapshot tests keeping the latest snapshot of each account
+func (s *StoreSuite) TestStoreLatestSnapshot(c *check.C) {
+	_, err := s.store.LatestSnapshot("a")
+	c.Assert(trace.IsNotFound(err), check.Equals, true)
+	older := types.NewSnapshotEvent("1.0.0", map[string]int64{types.GaugeUsers: 1})
+	older.SetAccountID("a")
+	setCreated(older, older.Metadata.Created.Add(-time.Hour))
+	newer := types.NewSnapshotEvent("1.1.0", map[string]int64{types.GaugeUsers: 2})
+	newer.SetAccountID("a")
+	other := types.NewSnapshotEvent("1.0.0", nil)
+	other.SetAccountID("b")
+	c.Assert(s.store.Put([]types.Event{newer, other}), check.IsNil)
+	// snapshots arriving out of order do not override newer ones
+	c.Assert(s.store.Put([]types.Event{older}), check.IsNil)
+	latest, err := s.store.LatestSnapshot("a")
+	c.Assert(err, check.IsNil)
+	c.Assert(latest, check.DeepEquals, newer)
+	latest, err = s.store.LatestSnapshot("b")
+	c.Assert(err, check.IsNil)
+	c.Assert(latest, check.DeepEquals, other)
+}
+
+// setCreated sets the creation time of the provided test event
+func setCreated(event types.Event, created time.Time) {
+	switch e := event.(type) {
+	case *types.UserEvent:
+		e.Metadata.Created = created
+	case *types.ServerEvent:
+		e.Metadata.Created = created
+	case *types.SnapshotEvent:
+		e.Metadata.Created = created
+	}
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2017 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/gravitational/reporting/types"
+
+	"github.com/gravitational/trace"
+	log "github.com/sirupsen/logrus"
+)
+
+// Subscriber is implemented by sinks that stream recorded events to
+// subscribers
+type Subscriber interface {
+	// Sink receives recorded events, Put must not block
+	Sink
+	// Subscribe returns a new subscription to events matching the filter
+	Subscribe(EventFilter) (*Subscription, error)
+}
+
+// TailConfig is the tail sink configuration
+type TailConfig struct {
+	// BufferSize is the number of events buffered for each subscriber
+	// before new events are dropped
+	BufferSize int
+}
+
+// CheckAndSetDefaults validates the config and sets default values
+func (c *TailConfig) CheckAndSetDefaults() error {
+	if c.BufferSize < 0 {
+		return trace.BadParameter("buffer size can't be negative")
+	}
+	if c.BufferSize == 0 {
+		c.BufferSize = tailBufferSize
+	}
+	return nil
+}
+
+// NewTailSink returns a new sink that streams events to subscribers,
+// events are dropped for subscribers that do not keep up so that slow
+// subscribers can't stall recording
+func NewTailSink(config TailConfig) (*tailSink, error) {
+	if err := config.CheckAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &tailSink{
+		TailConfig:    config,
+		subscriptions: make(map[*Subscription]struct{}),
+	}, nil
+}
+
+type tailSink struct {
+	TailConfig
+	sync.RWMutex
+	subscriptions map[*Subscription]struct{}
+}
+
+// Put sends the provided events to matching subscribers, it never blocks
+func (s *tailSink) Put(events []types.Event) error {
+	s.RLock()
+	defer s.RUnlock()
+	for sub := range s.subscriptions {
+		for _, event := range events {
+			if !sub.filter.matches(event) || !sub.filter.inRange(event) {
+				continue
+			}
+			select {
+			case sub.eventsCh <- event:
+			default:
+				atomic.AddUint64(&sub.dropped, 1)
+				log.Debugf("Subscriber buffer is full, dropping %v.", event)
+			}
+		}
+	}
+	return nil
+}
+
+// Subscribe returns a new subscription to events matching the filter
+func (s *tailSink) Subscribe(filter EventFilter) (*Subscription, error) {
+	sub := &Subscription{
+		sink:     s,
+		filter:   filter,
+		eventsCh: make(chan types.Event, s.BufferSize),
+	}
+	s.Lock()
+	defer s.Unlock()
+	s.subscriptions[sub] = struct{}{}
+	return sub, nil
+}
+
+// Subscription is a stream of events matching a filter
+type Subscription struct {
+	sink   *tailSink
+	filter EventFilter
+	// eventsCh is the bounded subscriber buffer
+	eventsCh chan types.Event
+	// dropped is the number of dropped events, accessed atomically
+	dropped uint64
+}
+
+// Events returns the channel with subscribed events, it is closed when
+// the subscription is closed
+func (s *Subscription) Events() <-chan types.Event {
+	return s.eventsCh
+}
+
+// Dropped returns the number of events dropped because the subscriber
+// did not keep up
+func (s *Subscription) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+// Close stops the subscription
+func (s *Subscription) Close() error {
+	s.sink.Lock()
+	defer s.sink.Unlock()
+	if _, ok := s.sink.subscriptions[s]; ok {
+		delete(s.sink.subscriptions, s)
+		close(s.eventsCh)
+	}
+	return nil
+}
+
+// inRange returns true if the event was created within the filter time range
+func (f EventFilter) inRange(event types.Event) bool {
+	created := event.GetMetadata().Created
+	return (f.From.IsZero() || !created.Before(f.From)) &&
+		(f.To.IsZero() || created.Before(f.To))
+}
+
+const (
+	// tailBufferSize is the default number of events buffered for
+	// each subscriber
+	tailBufferSize = 1024
+)
@@ -0,0 +1,163 @@
+/*
+Copyright 2017 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"time"
+
+	"github.com/gravitational/reporting"
+	"github.com/gravitational/reporting/types"
+
+	"github.com/google/uuid"
+	"github.com/gravitational/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	check "gopkg.in/check.v1"
+)
+
+type TailSuite struct{}
+
+var _ = check.Suite(&TailSuite{})
+
+// TestTailSink tests streaming events to subscribers with bounded buffers
+func (s *TailSuite) TestTailSink(c *check.C) {
+	sink, err := NewTailSink(TailConfig{BufferSize: 2})
+	c.Assert(err, check.IsNil)
+	users, err := sink.Subscribe(EventFilter{Name: types.EventTypeUser})
+	c.Assert(err, check.IsNil)
+	all, err := sink.Subscribe(EventFilter{})
+	c.Assert(err, check.IsNil)
+	user := types.NewUserLoginEvent(uuid.New().String())
+	server := types.NewServerLoginEvent(uuid.New().String())
+	c.Assert(sink.Put([]types.Event{user, server, user}), check.IsNil)
+	c.Assert(len(users.Events()), check.Equals, 2)
+	c.Assert(users.Dropped(), check.Equals, uint64(0))
+	// slow subscriber drops events instead of blocking
+	c.Assert(len(all.Events()), check.Equals, 2)
+	c.Assert(all.Dropped(), check.Equals, uint64(1))
+	c.Assert(users.Close(), check.IsNil)
+	c.Assert(users.Close(), check.IsNil)
+	c.Assert(sink.Put([]types.Event{user}), check.IsNil)
+	c.Assert(all.Dropped(), check.Equals, uint64(2))
+}
+
+// TestTailEvents tests streaming recorded events to admin clients
+func (s *TailSuite) TestTailEvents(c *check.C) {
+	tail, err := NewTailSink(TailConfig{})
+	c.Assert(err, check.IsNil)
+	_, err = NewServerWithConfig(ServerConfig{Sinks: []Sink{tail}, Tail: tail})
+	c.Assert(trace.IsBadParameter(err), check.Equals, true)
+	// tailing does not require a queryable sink and streams events even
+	// if saving them has failed
+	server, err := NewServerWithConfig(ServerConfig{
+		Sinks:  []Sink{failingSink{}},
+		Tail:   tail,
+		Admins: []string{"admin"},
+	})
+	c.Assert(err, check.IsNil)
+
+	err = server.TailEvents(&reporting.EventFilter{}, newTestTailStream(adminContext("user")))
+	c.Assert(trace.IsAccessDenied(err), check.Equals, true)
+
+	ctx, cancel := context.WithCancel(adminContext("admin"))
+	stream := newTestTailStream(ctx)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.TailEvents(&reporting.EventFilter{Name: types.EventTypeUser}, stream)
+	}()
+	user := types.NewUserLoginEvent(uuid.New().String())
+	// wait for the subscription before recording events
+	for len(tail.subscriptionsSnapshot()) == 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+	var grpcEvents reporting.GRPCEvents
+	for _, event := range []types.Event{types.NewServerLoginEvent(uuid.New().String()), user} {
+		grpcEvent, err := types.ToGRPCEvent(event)
+		c.Assert(err, check.IsNil)
+		grpcEvents.Events = append(grpcEvents.Events, grpcEvent)
+	}
+	_, err = server.Record(context.Background(), &grpcEvents)
+	c.Assert(err, check.NotNil)
+	select {
+	case grpcEvent := <-stream.eventsCh:
+		event, err := types.FromGRPCEvent(*grpcEvent)
+		c.Assert(err, check.IsNil)
+		c.Assert(event, check.DeepEquals, user)
+	case <-time.After(testTimeout):
+		c.Fatal("timeout waiting for events")
+	}
+	cancel()
+	c.Assert(<-errCh, check.IsNil)
+	c.Assert(tail.subscriptionsSnapshot(), check.HasLen, 0)
+}
+
+// failingSink is a sink that fails to save events
+type failingSink struct{}
+
+// Put returns an error
+func (failingSink) Put([]types.Event) error {
+	return trace.ConnectionProblem(nil, "sink is unavailable")
+}
+
+// subscriptionsSnapshot returns the list of active subscriptions
+func (s *tailSink) subscriptionsSnapshot() []*Subscription {
+	s.RLock()
+	defer s.RUnlock()
+	var subs []*Subscription
+	for sub := range s.subscriptions {
+		subs = append(subs, sub)
+	}
+	return subs
+}
+
+// testTailStream is a TailEvents server stream that sends events into a channel
+type testTailStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	eventsCh chan *reporting.GRPCEvent
+}
+
+func newTestTailStream(ctx context.Context) *testTailStream {
+	return &testTailStream{ctx: ctx, eventsCh: make(chan *reporting.GRPCEvent, 10)}
+}
+
+// Context returns the stream context
+func (s *testTailStream) Context() context.Context { return s.ctx }
+
+// Send sends the event into the channel
+func (s *testTailStream) Send(event *reporting.GRPCEvent) error {
+	s.eventsCh <- event
+	return nil
+}
+
+// adminContext returns a context of a gRPC call made with a verified
+// client certificate with the provided common name
+func adminContext(name string) context.Context {
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: name}}
+	return peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{
+			State: tls.ConnectionState{
+				VerifiedChains: [][]*x509.Certificate{{cert}},
+			},
+		},
+	})
+}
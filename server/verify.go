@@ -0,0 +1,110 @@
+/*
+Copyright 2017 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"github.com/gravitational/reporting"
+	"github.com/gravitational/reporting/types"
+
+	"github.com/gravitational/trace"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ed25519"
+)
+
+// VerifierConfig defines the event signature verification stage config
+type VerifierConfig struct {
+	// Keys are registered installation keys keyed by key ID
+	Keys map[string]InstallationKey
+	// Reject is whether events that can't be verified are rejected,
+	// by default they are accepted and flagged with the signature label
+	Reject bool
+}
+
+// InstallationKey is a registered installation public key
+type InstallationKey struct {
+	// PublicKey is the key event signatures are verified with
+	PublicKey ed25519.PublicKey
+	// AccountID is ID of the account the installation belongs to, only
+	// events of this account can be signed with the key
+	AccountID string
+}
+
+// Check validates the config
+func (c VerifierConfig) Check() error {
+	for id, key := range c.Keys {
+		if len(key.PublicKey) != ed25519.PublicKeySize {
+			return trace.BadParameter("invalid installation key %q", id)
+		}
+		if key.AccountID == "" {
+			return trace.BadParameter("installation key %q is missing account ID", id)
+		}
+	}
+	return nil
+}
+
+// verifyEvent verifies the signature of the gRPC event the provided event
+// has been converted from and sets the signature label to the verification
+// status, events that can't be verified are rejected or flagged depending
+// on the config
+func verifyEvent(config VerifierConfig, grpcEvent reporting.GRPCEvent, event types.Event) error {
+	status := types.SignatureValid
+	if err := verifyEventKey(config, grpcEvent, event); err != nil {
+		if config.Reject {
+			return trace.Wrap(err)
+		}
+		status = types.SignatureInvalid
+		if len(grpcEvent.Signature) == 0 {
+			status = types.SignatureMissing
+		}
+		log.Debugf("Flagging event %v: %v.", event.GetID(), err)
+	}
+	labeler, ok := event.(types.Labeler)
+	if !ok {
+		// unverified events that can't be flagged would be
+		// indistinguishable from verified ones
+		if status != types.SignatureValid {
+			return trace.BadParameter("%v event %v can't be flagged as unverified",
+				event.GetName(), event.GetID())
+		}
+		return nil
+	}
+	labeler.SetLabel(types.LabelSignature, status)
+	return nil
+}
+
+// verifyEventKey verifies the event signature with the installation key
+// it was signed with and checks that the key belongs to the event account
+func verifyEventKey(config VerifierConfig, grpcEvent reporting.GRPCEvent, event types.Event) error {
+	key := config.Keys[grpcEvent.KeyID]
+	err := types.VerifyGRPCEvent(grpcEvent, map[string]ed25519.PublicKey{
+		grpcEvent.KeyID: key.PublicKey,
+	})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if key.AccountID != event.GetAccountID() {
+		return trace.AccessDenied("key %q can't sign events of account %q",
+			grpcEvent.KeyID, event.GetAccountID())
+	}
+	return nil
+}
+
+// signatureStatus returns the signature verification status of the event,
+// it is empty if signatures are not verified
+func signatureStatus(event types.Event) string {
+	return event.GetMetadata().Labels[types.LabelSignature]
+}
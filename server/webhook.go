@@ -0,0 +1,242 @@
+/*
+Copyright 2017 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gravitational/reporting/types"
+
+	"github.com/gravitational/trace"
+	log "github.com/sirupsen/logrus"
+)
+
+// WebhookConfig is config for the webhook sink
+type WebhookConfig struct {
+	// Endpoints is the list of HTTP endpoints events are posted to
+	Endpoints []WebhookEndpoint `json:"endpoints"`
+	// Timeout is the timeout of a single request
+	Timeout time.Duration `json:"timeout,omitempty"`
+	// Retries is how many times a failed request is retried
+	Retries int `json:"retries,omitempty"`
+	// RetryInterval is how long to wait between retries
+	RetryInterval time.Duration `json:"retryInterval,omitempty"`
+	// Client is an optional HTTP client to use for requests
+	Client *http.Client `json:"-"`
+}
+
+// WebhookEndpoint defines a single webhook endpoint
+type WebhookEndpoint struct {
+	// URL is the endpoint URL
+	URL string `json:"url"`
+	// Secret is the key used to sign requests, it is shared with the
+	// receiver so it can verify the requests' authenticity
+	Secret string `json:"secret"`
+	// Match defines which events are posted to the endpoint
+	Match Match `json:"match"`
+}
+
+// CheckAndSetDefaults makes sure that webhook sink config is valid and
+// sets defaults for the unspecified fields
+func (c *WebhookConfig) CheckAndSetDefaults() error {
+	if len(c.Endpoints) == 0 {
+		return trace.BadParameter("webhook config is missing endpoints")
+	}
+	for _, endpoint := range c.Endpoints {
+		u, err := url.Parse(endpoint.URL)
+		if err != nil {
+			return trace.BadParameter("invalid webhook URL %q: %v", endpoint.URL, err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return trace.BadParameter("webhook URL %q should be http or https", endpoint.URL)
+		}
+		if endpoint.Secret == "" {
+			return trace.BadParameter("webhook %q is missing secret", endpoint.URL)
+		}
+		if err := endpoint.Match.Check(); err != nil {
+			return trace.Wrap(err, "webhook %q", endpoint.URL)
+		}
+	}
+	if c.Retries < 0 {
+		return trace.BadParameter("webhook retries can't be negative")
+	}
+	if c.Timeout == 0 {
+		c.Timeout = webhookTimeout
+	}
+	if c.RetryInterval == 0 {
+		c.RetryInterval = webhookRetryInterval
+	}
+	if c.Client == nil {
+		c.Client = &http.Client{}
+	}
+	return nil
+}
+
+// NewWebhookSink returns a new sink that posts batches of events as JSON
+// to the configured HTTP endpoints
+func NewWebhookSink(config WebhookConfig) (*webhookSink, error) {
+	if err := config.CheckAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &webhookSink{WebhookConfig: config}, nil
+}
+
+type webhookSink struct {
+	WebhookConfig
+}
+
+// WebhookPayload is the body of webhook requests
+type WebhookPayload struct {
+	// Events is the list of events in the same format they are sent
+	// by reporting clients
+	Events []json.RawMessage `json:"events"`
+}
+
+// Put posts the provided events to each endpoint whose rules match them
+func (s *webhookSink) Put(events []types.Event) error {
+	var errors []error
+	for _, endpoint := range s.Endpoints {
+		matched := endpoint.Match.Filter(events)
+		if len(matched) == 0 {
+			continue
+		}
+		if err := s.post(endpoint, matched); err != nil {
+			errors = append(errors, trace.Wrap(err, "webhook %q", endpoint.URL))
+		}
+	}
+	return trace.NewAggregate(errors...)
+}
+
+// post posts events to the endpoint retrying failed requests
+func (s *webhookSink) post(endpoint WebhookEndpoint, events []types.Event) error {
+	var payload WebhookPayload
+	for _, event := range events {
+		grpcEvent, err := types.ToGRPCEvent(event)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		payload.Events = append(payload.Events, grpcEvent.Data)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	for attempt := 0; ; attempt++ {
+		err = s.postOnce(endpoint, body)
+		if err == nil || !trace.IsConnectionProblem(err) || attempt >= s.Retries {
+			return trace.Wrap(err)
+		}
+		log.Debugf("Failed to post %v events to %v, will retry: %v.",
+			len(events), endpoint.URL, err)
+		time.Sleep(s.RetryInterval)
+	}
+}
+
+// postOnce makes a single signed request to the endpoint, failures that
+// may be retried are returned as connection problems
+func (s *webhookSink) postOnce(endpoint WebhookEndpoint, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(endpoint.Secret, timestamp, body))
+	client := *s.Client
+	client.Timeout = s.Timeout
+	resp, err := client.Do(req)
+	if err != nil {
+		return trace.ConnectionProblem(err, "failed to post to %v", endpoint.URL)
+	}
+	defer resp.Body.Close()
+	message, _ := ioutil.ReadAll(io.LimitReader(resp.Body, webhookMaxErrorSize))
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return trace.ConnectionProblem(nil, "%v responded with %v: %s",
+			endpoint.URL, resp.Status, message)
+	default:
+		return trace.BadParameter("%v responded with %v: %s",
+			endpoint.URL, resp.Status, message)
+	}
+}
+
+// SignWebhook returns the signature header value of a webhook request with
+// the provided timestamp and body: hex-encoded HMAC-SHA256 of the timestamp
+// and body joined with a dot, keyed with the endpoint secret
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return webhookSignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhook verifies the signature of a webhook request received at
+// the provided time and makes sure it is not older than maxAge to prevent
+// replays of captured requests
+func VerifyWebhook(secret string, header http.Header, body []byte, now time.Time, maxAge time.Duration) error {
+	timestamp := header.Get(WebhookTimestampHeader)
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return trace.AccessDenied("invalid webhook timestamp %q", timestamp)
+	}
+	age := now.Sub(time.Unix(seconds, 0))
+	if age > maxAge || age < -maxAge {
+		return trace.AccessDenied("webhook timestamp %v is outside of the allowed window", timestamp)
+	}
+	signature := header.Get(WebhookSignatureHeader)
+	if !strings.HasPrefix(signature, webhookSignaturePrefix) {
+		return trace.AccessDenied("missing webhook signature")
+	}
+	expected := SignWebhook(secret, timestamp, body)
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return trace.AccessDenied("webhook signature mismatch")
+	}
+	return nil
+}
+
+const (
+	// WebhookSignatureHeader is the header with the webhook request signature
+	WebhookSignatureHeader = "X-Reporting-Signature"
+	// WebhookTimestampHeader is the header with the webhook request Unix
+	// timestamp that is included in the signature
+	WebhookTimestampHeader = "X-Reporting-Timestamp"
+	// webhookSignaturePrefix is the prefix of the signature header value
+	// identifying the signature algorithm
+	webhookSignaturePrefix = "sha256="
+	// webhookTimeout is the default webhook request timeout
+	webhookTimeout = 10 * time.Second
+	// webhookRetryInterval is the default interval between retries
+	webhookRetryInterval = time.Second
+	// webhookMaxErrorSize is the max size of the error response body
+	// that is included in the error message
+	webhookMaxErrorSize = 1024
+)
@@ -0,0 +1,149 @@
+/*
+Copyright 2017 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	"github.com/gravitational/reporting"
+	"github.com/gravitational/reporting/types"
+
+	"github.com/google/uuid"
+	"github.com/gravitational/trace"
+	check "gopkg.in/check.v1"
+)
+
+type WebhookSuite struct{}
+
+var _ = check.Suite(&WebhookSuite{})
+
+// TestWebhook tests posting signed events to a webhook endpoint
+func (s *WebhookSuite) TestWebhook(c *check.C) {
+	// the handler does not assert as it runs outside of the test goroutine,
+	// decoded events or errors are sent back to the test instead
+	received := make(chan webhookResult, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			received <- webhookResult{err: err}
+			return
+		}
+		err = VerifyWebhook("secret", r.Header, body, time.Now(), time.Minute)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		events, err := decodeWebhook(body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+		received <- webhookResult{events: events, err: err}
+	}))
+	defer server.Close()
+	sink, err := NewWebhookSink(WebhookConfig{
+		Endpoints: []WebhookEndpoint{{
+			URL:    server.URL,
+			Secret: "secret",
+			Match:  Match{Names: []string{types.EventTypeUser}},
+		}},
+	})
+	c.Assert(err, check.IsNil)
+	userEvent := types.NewUserLoginEvent(uuid.New().String())
+	err = sink.Put([]types.Event{
+		types.NewServerLoginEvent(uuid.New().String()),
+		userEvent,
+	})
+	c.Assert(err, check.IsNil)
+	select {
+	case result := <-received:
+		c.Assert(result.err, check.IsNil)
+		c.Assert(result.events, check.DeepEquals, []types.Event{userEvent})
+	case <-time.After(testTimeout):
+		c.Fatal("timeout waiting for webhook")
+	}
+	// the receiver rejects requests signed with a different secret
+	sink.Endpoints[0].Secret = "other"
+	c.Assert(sink.Put([]types.Event{userEvent}), check.NotNil)
+}
+
+// webhookResult is the outcome of a webhook request received by the test
+// endpoint
+type webhookResult struct {
+	// events are the decoded events
+	events []types.Event
+	// err is the error reading or decoding the request
+	err error
+}
+
+// decodeWebhook decodes events from the webhook request body
+func decodeWebhook(body []byte) ([]types.Event, error) {
+	var payload WebhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var events []types.Event
+	for _, data := range payload.Events {
+		event, err := types.FromGRPCEvent(reporting.GRPCEvent{Data: data})
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// TestWebhookRetries tests retrying failed webhook requests
+func (s *WebhookSuite) TestWebhookRetries(c *check.C) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+	sink, err := NewWebhookSink(WebhookConfig{
+		Endpoints:     []WebhookEndpoint{{URL: server.URL, Secret: "secret"}},
+		Retries:       1,
+		RetryInterval: time.Millisecond,
+	})
+	c.Assert(err, check.IsNil)
+	events := []types.Event{types.NewUserLoginEvent(uuid.New().String())}
+	c.Assert(sink.Put(events), check.NotNil)
+	c.Assert(atomic.LoadInt32(&attempts), check.Equals, int32(2))
+	c.Assert(sink.Put(events), check.IsNil)
+	c.Assert(atomic.LoadInt32(&attempts), check.Equals, int32(3))
+}
+
+// TestVerifyWebhook tests webhook signature verification
+func (s *WebhookSuite) TestVerifyWebhook(c *check.C) {
+	body := []byte(`{"events":[]}`)
+	now := time.Now()
+	header := http.Header{}
+	timestamp := "1000"
+	header.Set(WebhookTimestampHeader, timestamp)
+	header.Set(WebhookSignatureHeader, SignWebhook("secret", timestamp, body))
+	c.Assert(VerifyWebhook("secret", header, body, time.Unix(1000, 0), time.Minute), check.IsNil)
+	c.Assert(VerifyWebhook("secret", header, body, now, time.Minute), check.NotNil)
+	c.Assert(VerifyWebhook("other", header, body, time.Unix(1000, 0), time.Minute), check.NotNil)
+	c.Assert(VerifyWebhook("secret", header, []byte("{}"), time.Unix(1000, 0), time.Minute), check.NotNil)
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2017 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package types
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gravitational/trace"
+)
+
+// ActionEvent represents a generic action performed by a subject, such as
+// "user logged out" or "cluster created"
+type ActionEvent struct {
+	// Kind is resource kind, for events it is "event"
+	Kind string `json:"kind"`
+	// Version is the event resource version
+	Version string `json:"version"`
+	// Metadata is the event metadata
+	Metadata Metadata `json:"metadata"`
+	// Spec is the event spec
+	Spec ActionEventSpec `json:"spec"`
+}
+
+// ActionEventSpec is action event specification
+type ActionEventSpec struct {
+	// ID is event ID, may be used for de-duplication
+	ID string `json:"id"`
+	// Action is event action, such as "logout"
+	Action string `json:"action"`
+	// AccountID is ID of account that triggered the event
+	AccountID string `json:"accountID"`
+	// SubjectType is the type of the subject that performed the action,
+	// such as "user" or "server"
+	SubjectType string `json:"subjectType"`
+	// SubjectID is anonymized ID of the subject that performed the action
+	SubjectID string `json:"subjectID"`
+	// Labels are arbitrary action attributes, such as resource kind
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// NewActionEvent creates an instance of an action event
+func NewActionEvent(subjectType, subjectID, action string, labels map[string]string) *ActionEvent {
+	return &ActionEvent{
+		Kind:    KindEvent,
+		Version: ResourceVersion,
+		Metadata: Metadata{
+			Name:    EventTypeAction,
+			Created: time.Now().UTC(),
+		},
+		Spec: ActionEventSpec{
+			ID:          uuid.New().String(),
+			Action:      action,
+			SubjectType: subjectType,
+			SubjectID:   subjectID,
+			Labels:      labels,
+		},
+	}
+}
+
+// GetName returns the event name
+func (e *ActionEvent) GetName() string { return e.Metadata.Name }
+
+// GetMetadata returns the event metadata
+func (e *ActionEvent) GetMetadata() Metadata { return e.Metadata }
+
+// GetID returns the event ID
+func (e *ActionEvent) GetID() string { return e.Spec.ID }
+
+// GetAction returns the event action
+func (e *ActionEvent) GetAction() string { return e.Spec.Action }
+
+// GetAccountID returns the event account ID
+func (e *ActionEvent) GetAccountID() string { return e.Spec.AccountID }
+
+// SetAccountID sets the event account ID
+func (e *ActionEvent) SetAccountID(id string) {
+	e.Spec.AccountID = id
+}
+
+// SetLabel sets the event metadata label
+func (e *ActionEvent) SetLabel(key, value string) {
+	e.Metadata.SetLabel(key, value)
+}
+
+// MapIdentifiers replaces the subject ID with the value returned by fn
+func (e *ActionEvent) MapIdentifiers(fn func(string) string) {
+	e.Spec.SubjectID = fn(e.Spec.SubjectID)
+}
+
+// Validate checks the event values
+func (e *ActionEvent) Validate(config ValidationConfig) error {
+	if err := validateID(e.Spec.ID); err != nil {
+		return trace.Wrap(err)
+	}
+	// action events are generic so any action is allowed
+	if err := config.validateString("action", e.Spec.Action, true); err != nil {
+		return trace.Wrap(err)
+	}
+	if err := config.validateString("account ID", e.Spec.AccountID, false); err != nil {
+		return trace.Wrap(err)
+	}
+	if err := config.validateString("subject type", e.Spec.SubjectType, true); err != nil {
+		return trace.Wrap(err)
+	}
+	return trace.Wrap(config.validateString("subject ID", e.Spec.SubjectID, true))
+}
+
+func init() {
+	RegisterEvent(EventTypeAction, actionEventSchema, func() Event { return &ActionEvent{} })
+}
+
+// actionEventSchema is the action event spec schema
+var actionEventSchema = fmt.Sprintf(`{
+  "type": "object",
+  "additionalProperties": false,
+  "required": ["id", "action", "accountID", "subjectType", "subjectID"],
+  "properties": {
+    "id": {"type": "string"},
+    "action": {"type": "string", "minLength": 1},
+    "accountID": {"type": "string"},
+    "subjectType": {"type": "string", "minLength": 1},
+    "subjectID": {"type": "string"},
+    "labels": {
+      "type": "object",
+      "maxProperties": %v,
+      "additionalProperties": false,
+      "patternProperties": {
+        "^[a-zA-Z0-9_./:-]{1,%v}$": {"type": "string", "maxLength": %v}
+      }
+    }
+  }
+}`, MaxLabels, MaxLabelKeyLength, MaxLabelValueLength)
+
+const (
+	// MaxLabels is the maximum number of labels an action event can have
+	MaxLabels = 32
+	// MaxLabelKeyLength is the maximum length of an action event label key
+	MaxLabelKeyLength = 64
+	// MaxLabelValueLength is the maximum length of an action event label value
+	MaxLabelValueLength = 256
+)
@@ -0,0 +1,115 @@
+/*
+Copyright 2017 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package types
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gravitational/trace"
+)
+
+// ConsentEvent records a change of telemetry consent, it is the only
+// event sent by clients that opted out of reporting
+type ConsentEvent struct {
+	// Kind is resource kind, for events it is "event"
+	Kind string `json:"kind"`
+	// Version is the event resource version
+	Version string `json:"version"`
+	// Metadata is the event metadata
+	Metadata Metadata `json:"metadata"`
+	// Spec is the event spec
+	Spec ConsentEventSpec `json:"spec"`
+}
+
+// ConsentEventSpec is consent event specification
+type ConsentEventSpec struct {
+	// ID is event ID, may be used for de-duplication
+	ID string `json:"id"`
+	// Action is the consent change, such as "opt-out"
+	Action string `json:"action"`
+	// AccountID is ID of account that changed consent
+	AccountID string `json:"accountID"`
+}
+
+// NewOptOutEvent creates an instance of "opt out" consent event
+func NewOptOutEvent() *ConsentEvent {
+	return &ConsentEvent{
+		Kind:    KindEvent,
+		Version: ResourceVersion,
+		Metadata: Metadata{
+			Name:    EventTypeConsent,
+			Created: time.Now().UTC(),
+		},
+		Spec: ConsentEventSpec{
+			ID:     uuid.New().String(),
+			Action: EventActionOptOut,
+		},
+	}
+}
+
+// GetName returns the event name
+func (e *ConsentEvent) GetName() string { return e.Metadata.Name }
+
+// GetMetadata returns the event metadata
+func (e *ConsentEvent) GetMetadata() Metadata { return e.Metadata }
+
+// GetID returns the event ID
+func (e *ConsentEvent) GetID() string { return e.Spec.ID }
+
+// GetAction returns the event action
+func (e *ConsentEvent) GetAction() string { return e.Spec.Action }
+
+// GetAccountID returns the event account ID
+func (e *ConsentEvent) GetAccountID() string { return e.Spec.AccountID }
+
+// SetAccountID sets the event account ID
+func (e *ConsentEvent) SetAccountID(id string) {
+	e.Spec.AccountID = id
+}
+
+// SetLabel sets the event metadata label
+func (e *ConsentEvent) SetLabel(key, value string) {
+	e.Metadata.SetLabel(key, value)
+}
+
+// Validate checks the event values
+func (e *ConsentEvent) Validate(config ValidationConfig) error {
+	if err := validateID(e.Spec.ID); err != nil {
+		return trace.Wrap(err)
+	}
+	if e.Spec.Action != EventActionOptOut {
+		return trace.BadParameter("unsupported consent action %q", e.Spec.Action)
+	}
+	return trace.Wrap(config.validateString("account ID", e.Spec.AccountID, false))
+}
+
+func init() {
+	RegisterEvent(EventTypeConsent, consentEventSchema, func() Event { return &ConsentEvent{} })
+}
+
+// consentEventSchema is the consent event spec schema
+const consentEventSchema = `{
+  "type": "object",
+  "additionalProperties": false,
+  "required": ["id", "action", "accountID"],
+  "properties": {
+    "id": {"type": "string"},
+    "action": {"type": "string"},
+    "accountID": {"type": "string"}
+  }
+}`
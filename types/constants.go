@@ -18,15 +18,48 @@ package types
 
 const (
 	// ResourceVersion is the current event resource version
-	ResourceVersion = "v2"
+	ResourceVersion = V3
+	// V3 is the resource version that added metadata labels
+	V3 = "v3"
+	// V2 is the initial resource version
+	V2 = "v2"
+	// HeartbeatVersion is the resource version heartbeats are marshaled
+	// in by default, it is understood by all clients
+	HeartbeatVersion = V2
+	// SupportedVersionsTrailer is the gRPC trailer with the resource
+	// versions supported by the server, it is set when the server rejects
+	// events of an unsupported version
+	SupportedVersionsTrailer = "reporting-supported-versions"
 	// KindEvent is the event resource kind
 	KindEvent = "event"
 	// EventTypeServer is the server-related event type
 	EventTypeServer = "server"
 	// EventTypeUser is the user-related event type
 	EventTypeUser = "user"
+	// EventTypeAction is the generic action event type
+	EventTypeAction = "action"
+	// EventTypeUsage is the metered usage event type
+	EventTypeUsage = "usage"
+	// EventTypeSnapshot is the installation snapshot event type
+	EventTypeSnapshot = "snapshot"
+	// EventTypeConsent is the telemetry consent event type
+	EventTypeConsent = "consent"
 	// EventActionLogin is the event login action
 	EventActionLogin = "login"
+	// EventActionLogout is the event logout action
+	EventActionLogout = "logout"
+	// EventActionCreate is the resource creation event action
+	EventActionCreate = "create"
+	// EventActionDelete is the resource deletion event action
+	EventActionDelete = "delete"
+	// EventActionUse is the feature usage event action
+	EventActionUse = "use"
+	// EventActionOptOut is the telemetry opt-out consent event action
+	EventActionOptOut = "opt-out"
+	// GaugeUsers is the snapshot gauge with number of registered users
+	GaugeUsers = "users"
+	// GaugeNodes is the snapshot gauge with total number of nodes
+	GaugeNodes = "nodes"
 	// KindHeartbeat is the heartbeat resource kind
 	KindHeartbeat = "heartbeat"
 	// NotificationUsage is the usage limit notification type
@@ -39,4 +72,20 @@ const (
 	SeverityWarning = "warning"
 	// SeverityError is error notification severity
 	SeverityError = "error"
+	// LicenseStatusActive is the status of a valid unexpired license
+	LicenseStatusActive = "active"
+	// LicenseStatusExpired is the status of an expired license
+	LicenseStatusExpired = "expired"
+	// LicenseStatusInvalid is the status of a revoked or unknown license
+	LicenseStatusInvalid = "invalid"
+	// LabelSignature is the label set on events by servers that verify
+	// event signatures, its value is the status: valid, missing or invalid
+	LabelSignature = "signature"
+	// SignatureValid is the signature label value of verified events
+	SignatureValid = "valid"
+	// SignatureMissing is the signature label value of unsigned events
+	SignatureMissing = "missing"
+	// SignatureInvalid is the signature label value of events with
+	// signatures that could not be verified
+	SignatureInvalid = "invalid"
 )
@@ -18,7 +18,8 @@ package types
 
 import (
 	"encoding/json"
-	"fmt"
+	"reflect"
+	"sync"
 	"time"
 
 	"github.com/gravitational/reporting"
@@ -34,6 +35,13 @@ type Event interface {
 	GetName() string
 	// GetMetadata returns the event metadata
 	GetMetadata() Metadata
+	// GetID returns the event ID
+	GetID() string
+	// GetAction returns the event action, or an empty string if the
+	// event kind does not have one
+	GetAction() string
+	// GetAccountID returns the event account ID
+	GetAccountID() string
 	// SetAccountID sets the event account ID
 	SetAccountID(string)
 }
@@ -44,6 +52,29 @@ type Metadata struct {
 	Name string `json:"name"`
 	// Created is the event creation timestamp
 	Created time.Time `json:"created"`
+	// Labels are optional resource labels, added in v3
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// SetLabel sets the metadata label
+func (m *Metadata) SetLabel(key, value string) {
+	if m.Labels == nil {
+		m.Labels = make(map[string]string)
+	}
+	m.Labels[key] = value
+}
+
+// Labeler is implemented by events that support setting metadata labels
+type Labeler interface {
+	// SetLabel sets the event metadata label
+	SetLabel(key, value string)
+}
+
+// IdentifierMapper is implemented by events with identifier fields that
+// must be anonymized, such as server or user IDs
+type IdentifierMapper interface {
+	// MapIdentifiers replaces identifier fields with values returned by fn
+	MapIdentifiers(fn func(string) string)
 }
 
 // ServerEvent represents server-related event, such as "logged into server"
@@ -70,7 +101,9 @@ type ServerEventSpec struct {
 	ServerID string `json:"serverID"`
 }
 
-// NewServerLoginEvent creates an instance of "server login" event
+// NewServerLoginEvent creates an instance of "server login" event, the
+// server ID is replaced with a pseudonym by clients with pseudonymization
+// enabled
 func NewServerLoginEvent(serverID string) *ServerEvent {
 	return &ServerEvent{
 		Kind:    KindEvent,
@@ -93,11 +126,44 @@ func (e *ServerEvent) GetName() string { return e.Metadata.Name }
 // GetMetadata returns the event metadata
 func (e *ServerEvent) GetMetadata() Metadata { return e.Metadata }
 
+// GetID returns the event ID
+func (e *ServerEvent) GetID() string { return e.Spec.ID }
+
+// GetAction returns the event action
+func (e *ServerEvent) GetAction() string { return e.Spec.Action }
+
+// GetAccountID returns the event account ID
+func (e *ServerEvent) GetAccountID() string { return e.Spec.AccountID }
+
 // SetAccountID sets the event account ID
 func (e *ServerEvent) SetAccountID(id string) {
 	e.Spec.AccountID = id
 }
 
+// SetLabel sets the event metadata label
+func (e *ServerEvent) SetLabel(key, value string) {
+	e.Metadata.SetLabel(key, value)
+}
+
+// MapIdentifiers replaces the server ID with the value returned by fn
+func (e *ServerEvent) MapIdentifiers(fn func(string) string) {
+	e.Spec.ServerID = fn(e.Spec.ServerID)
+}
+
+// Validate checks the event values
+func (e *ServerEvent) Validate(config ValidationConfig) error {
+	if err := validateID(e.Spec.ID); err != nil {
+		return trace.Wrap(err)
+	}
+	if err := config.validateAction(e.Spec.Action); err != nil {
+		return trace.Wrap(err)
+	}
+	if err := config.validateString("account ID", e.Spec.AccountID, false); err != nil {
+		return trace.Wrap(err)
+	}
+	return trace.Wrap(config.validateString("server ID", e.Spec.ServerID, true))
+}
+
 // UserEvent represents user-related event, such as "user logged in"
 type UserEvent struct {
 	// Kind is resource kind, for events it is "event"
@@ -122,7 +188,8 @@ type UserEventSpec struct {
 	UserID string `json:"userID"`
 }
 
-// NewUserLoginEvent creates an instance of "user login" event
+// NewUserLoginEvent creates an instance of "user login" event, the user
+// ID is replaced with a pseudonym by clients with pseudonymization enabled
 func NewUserLoginEvent(userID string) *UserEvent {
 	return &UserEvent{
 		Kind:    KindEvent,
@@ -145,11 +212,49 @@ func (e *UserEvent) GetName() string { return e.Metadata.Name }
 // GetMetadata returns the event metadata
 func (e *UserEvent) GetMetadata() Metadata { return e.Metadata }
 
+// GetID returns the event ID
+func (e *UserEvent) GetID() string { return e.Spec.ID }
+
+// GetAction returns the event action
+func (e *UserEvent) GetAction() string { return e.Spec.Action }
+
+// GetAccountID returns the event account ID
+func (e *UserEvent) GetAccountID() string { return e.Spec.AccountID }
+
 // SetAccountID sets the event account id
 func (e *UserEvent) SetAccountID(id string) {
 	e.Spec.AccountID = id
 }
 
+// SetLabel sets the event metadata label
+func (e *UserEvent) SetLabel(key, value string) {
+	e.Metadata.SetLabel(key, value)
+}
+
+// MapIdentifiers replaces the user ID with the value returned by fn
+func (e *UserEvent) MapIdentifiers(fn func(string) string) {
+	e.Spec.UserID = fn(e.Spec.UserID)
+}
+
+// Validate checks the event values
+func (e *UserEvent) Validate(config ValidationConfig) error {
+	if err := validateID(e.Spec.ID); err != nil {
+		return trace.Wrap(err)
+	}
+	if err := config.validateAction(e.Spec.Action); err != nil {
+		return trace.Wrap(err)
+	}
+	if err := config.validateString("account ID", e.Spec.AccountID, false); err != nil {
+		return trace.Wrap(err)
+	}
+	return trace.Wrap(config.validateString("user ID", e.Spec.UserID, true))
+}
+
+func init() {
+	RegisterEvent(EventTypeServer, serverEventSchema, func() Event { return &ServerEvent{} })
+	RegisterEvent(EventTypeUser, userEventSchema, func() Event { return &UserEvent{} })
+}
+
 // ToGRPCEvent converts provided event to the format used by gRPC server/client
 func ToGRPCEvent(event Event) (*reporting.GRPCEvent, error) {
 	payload, err := json.Marshal(event)
@@ -161,45 +266,75 @@ func ToGRPCEvent(event Event) (*reporting.GRPCEvent, error) {
 	}, nil
 }
 
+// ToGRPCEventVersion converts provided event to the format used by gRPC
+// server/client using the provided older resource version, it is used
+// when talking to servers that do not support the current version
+func ToGRPCEventVersion(event Event, version string) (*reporting.GRPCEvent, error) {
+	payload, err := downgrade(KindEvent, version, event)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &reporting.GRPCEvent{
+		Data: payload,
+	}, nil
+}
+
 // FromGRPCEvent converts event from the format used by gRPC server/client
+// and validates it using the default validation rules
 func FromGRPCEvent(grpcEvent reporting.GRPCEvent) (Event, error) {
-	var header resourceHeader
-	if err := json.Unmarshal(grpcEvent.Data, &header); err != nil {
+	event, err := DecodeGRPCEvent(grpcEvent)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if err := ValidateEvent(event, ValidationConfig{}); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return event, nil
+}
+
+// DecodeGRPCEvent converts event from the format used by gRPC server/client
+// without semantic validation, such as the event age, it is used to decode
+// events that have been validated already, the event may be either
+// JSON-encoded or typed
+func DecodeGRPCEvent(grpcEvent reporting.GRPCEvent) (Event, error) {
+	if grpcEvent.Event != nil {
+		event, err := fromGRPCTypedEvent(grpcEvent)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		return event, nil
+	}
+	raw, header, err := decodeResource(grpcEvent.Data)
+	if err != nil {
 		return nil, trace.Wrap(err)
 	}
 	if header.Kind != KindEvent {
 		return nil, trace.BadParameter("expected kind %q, got %q",
 			KindEvent, header.Kind)
 	}
+	data := grpcEvent.Data
 	if header.Version != ResourceVersion {
-		return nil, trace.BadParameter("expected resource version %q, got %q",
-			ResourceVersion, header.Version)
-	}
-	switch header.Metadata.Name {
-	case EventTypeServer:
-		var event ServerEvent
-		err := unmarshalWithSchema(
-			getServerEventSchema(), grpcEvent.Data, &event)
-		if err != nil {
+		if data, err = upgrade(KindEvent, data); err != nil {
 			return nil, trace.Wrap(err)
 		}
-		return &event, nil
-	case EventTypeUser:
-		var event UserEvent
-		err := unmarshalWithSchema(
-			getUserEventSchema(), grpcEvent.Data, &event)
-		if err != nil {
+		if raw, header, err = decodeResource(data); err != nil {
 			return nil, trace.Wrap(err)
 		}
-		return &event, nil
-	default:
-		return nil, trace.BadParameter("unknown event type %q", header.Metadata.Name)
 	}
+	eventType, err := getEventType(header.Metadata.Name)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	event := eventType.factory()
+	if err := unmarshalRawWithSchema(eventType.schema, raw, data, event); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return event, nil
 }
 
 // FromGRPCEvents converts a series of events from the format used by gRPC server/client
 func FromGRPCEvents(grpcEvents reporting.GRPCEvents) ([]Event, error) {
-	var events []Event
+	events := make([]Event, 0, len(grpcEvents.Events))
 	for _, grpcEvent := range grpcEvents.Events {
 		event, err := FromGRPCEvent(*grpcEvent)
 		if err != nil {
@@ -220,6 +355,22 @@ type resourceHeader struct {
 	Metadata Metadata `json:"metadata"`
 }
 
+// decodeResource unmarshals the resource into a generic map that is used
+// for schema validation and returns it along with the resource header
+func decodeResource(data []byte) (map[string]interface{}, *resourceHeader, error) {
+	raw := map[string]interface{}{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, nil, trace.Wrap(err)
+	}
+	var header resourceHeader
+	header.Kind, _ = raw["kind"].(string)
+	header.Version, _ = raw["version"].(string)
+	if metadata, ok := raw["metadata"].(map[string]interface{}); ok {
+		header.Metadata.Name, _ = metadata["name"].(string)
+	}
+	return raw, &header, nil
+}
+
 // schemaTemplate is the event resource schema template
 const schemaTemplate = `{
   "type": "object",
@@ -227,25 +378,24 @@ const schemaTemplate = `{
   "required": ["kind", "version", "metadata", "spec"],
   "properties": {
     "kind": {"type": "string"},
-    "version": {"type": "string", "default": "v2"},
+    "version": {"type": "string", "default": "v3"},
     "metadata": {
       "type": "object",
       "additionalProperties": false,
       "required": ["name", "created"],
       "properties": {
         "name": {"type": "string"},
-        "created": {"type": "string"}
+        "created": {"type": "string"},
+        "labels": {
+          "type": "object",
+          "additionalProperties": {"type": "string"}
+        }
       }
     },
     "spec": %v
   }
 }`
 
-// getServerEventSchema returns full server event JSON schema
-func getServerEventSchema() string {
-	return fmt.Sprintf(schemaTemplate, serverEventSchema)
-}
-
 // serverEventSchema is the server event spec schema
 const serverEventSchema = `{
   "type": "object",
@@ -259,11 +409,6 @@ const serverEventSchema = `{
   }
 }`
 
-// getUserEventSchema returns full user event JSON schema
-func getUserEventSchema() string {
-	return fmt.Sprintf(schemaTemplate, userEventSchema)
-}
-
 // userEventSchema is the user event spec schema
 const userEventSchema = `{
   "type": "object",
@@ -280,23 +425,32 @@ const userEventSchema = `{
 // unmarshalWithSchema unmarshals the provided data into the provided object
 // using specified JSON schema
 func unmarshalWithSchema(objectSchema string, data []byte, object interface{}) error {
-	schema, err := jsonschema.New([]byte(objectSchema))
-	if err != nil {
-		return trace.Wrap(err)
-	}
 	raw := map[string]interface{}{}
 	if err := json.Unmarshal(data, &raw); err != nil {
 		return trace.Wrap(err)
 	}
-	processed, err := schema.ProcessObject(raw)
+	return trace.Wrap(unmarshalRawWithSchema(objectSchema, raw, data, object))
+}
+
+// unmarshalRawWithSchema unmarshals the provided data that has already been
+// decoded into raw map into the provided object using specified JSON schema
+func unmarshalRawWithSchema(objectSchema string, raw map[string]interface{}, data []byte, object interface{}) error {
+	schema, err := compileSchema(objectSchema)
 	if err != nil {
 		return trace.Wrap(err)
 	}
-	bytes, err := json.Marshal(processed)
+	processed, err := schema.ProcessObject(raw)
 	if err != nil {
 		return trace.Wrap(err)
 	}
-	if err := json.Unmarshal(bytes, object); err != nil {
+	// processing only changes the object when it sets default values,
+	// otherwise the original data can be unmarshaled as is
+	if !reflect.DeepEqual(processed, raw) {
+		if data, err = json.Marshal(processed); err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	if err := json.Unmarshal(data, object); err != nil {
 		return trace.Wrap(err)
 	}
 	return nil
@@ -304,7 +458,7 @@ func unmarshalWithSchema(objectSchema string, data []byte, object interface{}) e
 
 // marshalWithSchema marshals the provided objects while checking the specified schema
 func marshalWithSchema(objectSchema string, object interface{}) ([]byte, error) {
-	schema, err := jsonschema.New([]byte(objectSchema))
+	schema, err := compileSchema(objectSchema)
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
@@ -318,3 +472,20 @@ func marshalWithSchema(objectSchema string, object interface{}) ([]byte, error)
 	}
 	return bytes, nil
 }
+
+// compileSchema returns the compiled JSON schema, schemas are compiled once
+// and cached
+func compileSchema(objectSchema string) (*jsonschema.JSONSchema, error) {
+	if schema, ok := compiledSchemas.Load(objectSchema); ok {
+		return schema.(*jsonschema.JSONSchema), nil
+	}
+	schema, err := jsonschema.New([]byte(objectSchema))
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	compiledSchemas.Store(objectSchema, schema)
+	return schema, nil
+}
+
+// compiledSchemas caches compiled JSON schemas keyed by schema source
+var compiledSchemas sync.Map
@@ -19,6 +19,7 @@ package types
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"time"
 
 	"github.com/gravitational/trace"
@@ -40,6 +41,22 @@ type Heartbeat struct {
 type HeartbeatSpec struct {
 	// Notifications is a list of notifications sent with the heartbeat
 	Notifications []Notification `json:"notifications,omitempty"`
+	// License is the license status, it is not set if the control plane
+	// does not track the license of the receiver
+	License *LicenseStatus `json:"license,omitempty"`
+	// Features is a list of enabled feature flags
+	Features []string `json:"features,omitempty"`
+	// PollInterval is the recommended interval until the next heartbeat
+	// is requested, zero means the receiver picks the interval
+	PollInterval time.Duration `json:"pollInterval,omitempty"`
+}
+
+// LicenseStatus describes the state of the receiver license
+type LicenseStatus struct {
+	// Status is the license status: active, expired or invalid
+	Status string `json:"status"`
+	// Expires is the license expiration time
+	Expires time.Time `json:"expires"`
 }
 
 // Notification represents a user notification message
@@ -52,6 +69,29 @@ type Notification struct {
 	Text string `json:"text"`
 	// HTML is the notification HTML
 	HTML string `json:"html"`
+	// ID identifies the notification across heartbeats
+	ID string `json:"id,omitempty"`
+	// NotBefore is the time the notification becomes active, zero means
+	// the notification is active right away
+	NotBefore time.Time `json:"notBefore"`
+	// NotAfter is the time the notification expires, zero means the
+	// notification does not expire
+	NotAfter time.Time `json:"notAfter"`
+	// Dismissible is whether users can dismiss the notification
+	Dismissible bool `json:"dismissible,omitempty"`
+	// URL is the optional call-to-action link
+	URL string `json:"url,omitempty"`
+}
+
+// IsActive returns true if the notification is active at the provided time
+func (n Notification) IsActive(now time.Time) bool {
+	if !n.NotBefore.IsZero() && now.Before(n.NotBefore) {
+		return false
+	}
+	if !n.NotAfter.IsZero() && !now.Before(n.NotAfter) {
+		return false
+	}
+	return true
 }
 
 // NewHeartbeat returns a new heartbeat
@@ -75,10 +115,97 @@ func (h *Heartbeat) GetName() string { return h.Metadata.Name }
 // GetMetadata returns the heartbeat metadata
 func (h *Heartbeat) GetMetadata() Metadata { return h.Metadata }
 
-// UnmarshalHeartbeat unmarshals heartbeat with schema validation
+// HasFeature returns true if the feature flag is enabled by the heartbeat
+func (h *Heartbeat) HasFeature(name string) bool {
+	for _, feature := range h.Spec.Features {
+		if feature == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the heartbeat values
+func (h *Heartbeat) Validate(config ValidationConfig) error {
+	if err := config.validateMetadata(h.Metadata); err != nil {
+		return trace.Wrap(err)
+	}
+	for _, n := range h.Spec.Notifications {
+		if err := n.Validate(config); err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	if h.Spec.License != nil {
+		switch h.Spec.License.Status {
+		case LicenseStatusActive, LicenseStatusExpired, LicenseStatusInvalid:
+		default:
+			return trace.BadParameter("unsupported license status %q", h.Spec.License.Status)
+		}
+	}
+	for _, feature := range h.Spec.Features {
+		if err := config.validateString("feature", feature, true); err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	if h.Spec.PollInterval < 0 {
+		return trace.BadParameter("poll interval can't be negative")
+	}
+	return nil
+}
+
+// Validate checks the notification values
+func (n Notification) Validate(config ValidationConfig) error {
+	if err := config.validateString("notification type", n.Type, true); err != nil {
+		return trace.Wrap(err)
+	}
+	switch n.Severity {
+	case SeverityInfo, SeverityWarning, SeverityError:
+	default:
+		return trace.BadParameter("unsupported notification severity %q", n.Severity)
+	}
+	if err := config.validateString("notification text", n.Text, true); err != nil {
+		return trace.Wrap(err)
+	}
+	if err := config.validateString("notification HTML", n.HTML, false); err != nil {
+		return trace.Wrap(err)
+	}
+	if err := config.validateString("notification ID", n.ID, n.Dismissible); err != nil {
+		return trace.Wrap(err)
+	}
+	if !n.NotBefore.IsZero() && !n.NotAfter.IsZero() && !n.NotAfter.After(n.NotBefore) {
+		return trace.BadParameter("notification expires at %v before it becomes active at %v",
+			n.NotAfter, n.NotBefore)
+	}
+	if n.URL != "" {
+		if err := config.validateString("notification URL", n.URL, true); err != nil {
+			return trace.Wrap(err)
+		}
+		u, err := url.Parse(n.URL)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		if u.Scheme != "https" && u.Scheme != "http" {
+			return trace.BadParameter("notification URL %q is not an HTTP URL", n.URL)
+		}
+	}
+	return nil
+}
+
+// UnmarshalHeartbeat unmarshals unsigned heartbeat with schema validation
+// and notification HTML sanitization using the default validation rules
 func UnmarshalHeartbeat(bytes []byte) (*Heartbeat, error) {
-	var header resourceHeader
-	if err := json.Unmarshal(bytes, &header); err != nil {
+	heartbeat, err := UnmarshalSignedHeartbeat(bytes, nil, ValidationConfig{})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return heartbeat, nil
+}
+
+// unmarshalHeartbeat unmarshals heartbeat with schema validation and
+// notification HTML sanitization
+func unmarshalHeartbeat(bytes []byte, config ValidationConfig) (*Heartbeat, error) {
+	raw, header, err := decodeResource(bytes)
+	if err != nil {
 		return nil, trace.Wrap(err)
 	}
 	if header.Kind != KindHeartbeat {
@@ -86,21 +213,50 @@ func UnmarshalHeartbeat(bytes []byte) (*Heartbeat, error) {
 			KindHeartbeat, header.Kind)
 	}
 	if header.Version != ResourceVersion {
-		return nil, trace.BadParameter("expected resource version %q, got %q",
-			ResourceVersion, header.Version)
+		if bytes, err = upgrade(KindHeartbeat, bytes); err != nil {
+			return nil, trace.Wrap(err)
+		}
+		if raw, _, err = decodeResource(bytes); err != nil {
+			return nil, trace.Wrap(err)
+		}
 	}
 	var heartbeat Heartbeat
-	err := unmarshalWithSchema(
-		getHeartbeatSchema(), bytes, &heartbeat)
+	err = unmarshalRawWithSchema(heartbeatFullSchema, raw, bytes, &heartbeat)
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
+	if err := sanitizeHeartbeat(&heartbeat, config.StrictHTML); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if err := heartbeat.Validate(config); err != nil {
+		return nil, trace.Wrap(err)
+	}
 	return &heartbeat, nil
 }
 
-// MarshalHeartbeat marshals heartbeat with schema validation
+// MarshalHeartbeat marshals heartbeat with schema validation and
+// notification HTML sanitization using HeartbeatVersion, so that it can
+// be read by clients that do not support the current resource version
 func MarshalHeartbeat(h Heartbeat) ([]byte, error) {
-	bytes, err := marshalWithSchema(getHeartbeatSchema(), h)
+	bytes, err := MarshalHeartbeatVersion(h, HeartbeatVersion)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return bytes, nil
+}
+
+// MarshalHeartbeatVersion marshals heartbeat with schema validation and
+// notification HTML sanitization using the provided resource version, it
+// is used when sending heartbeats to clients known to support it
+func MarshalHeartbeatVersion(h Heartbeat, version string) ([]byte, error) {
+	if err := sanitizeHeartbeat(&h, false); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	bytes, err := marshalWithSchema(heartbeatFullSchema, h)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	bytes, err = downgrade(KindHeartbeat, version, json.RawMessage(bytes))
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
@@ -121,14 +277,31 @@ const heartbeatSchema = `{
           "type": {"type": "string"},
           "severity": {"type": "string"},
           "text": {"type": "string"},
-          "html": {"type": "string"}
+          "html": {"type": "string"},
+          "id": {"type": "string"},
+          "notBefore": {"type": "string"},
+          "notAfter": {"type": "string"},
+          "dismissible": {"type": "boolean"},
+          "url": {"type": "string"}
         }
       }
-    }
+    },
+    "license": {
+      "type": "object",
+      "required": ["status"],
+      "additionalProperties": false,
+      "properties": {
+        "status": {"type": "string"},
+        "expires": {"type": "string"}
+      }
+    },
+    "features": {
+      "type": "array",
+      "items": {"type": "string"}
+    },
+    "pollInterval": {"type": "integer", "minimum": 0}
   }
 }`
 
-// getHeartbeatSchema returns the full heartbeat resource schema
-func getHeartbeatSchema() string {
-	return fmt.Sprintf(schemaTemplate, heartbeatSchema)
-}
+// heartbeatFullSchema is the full heartbeat resource schema
+var heartbeatFullSchema = fmt.Sprintf(schemaTemplate, heartbeatSchema)
@@ -0,0 +1,254 @@
+/*
+Copyright 2017 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package types
+
+import (
+	"time"
+
+	"github.com/gravitational/reporting"
+
+	"github.com/gravitational/trace"
+)
+
+// ToGRPCTypedEvent converts provided event to the typed format used by
+// gRPC server/client, only server and user events have typed messages
+func ToGRPCTypedEvent(event Event) (*reporting.GRPCEvent, error) {
+	switch e := event.(type) {
+	case *ServerEvent:
+		return &reporting.GRPCEvent{
+			Event: &reporting.GRPCEvent_Server{
+				Server: &reporting.GRPCServerEvent{
+					Version:   e.Version,
+					Metadata:  toGRPCMetadata(e.Metadata),
+					ID:        e.Spec.ID,
+					Action:    e.Spec.Action,
+					AccountID: e.Spec.AccountID,
+					ServerID:  e.Spec.ServerID,
+				},
+			},
+		}, nil
+	case *UserEvent:
+		return &reporting.GRPCEvent{
+			Event: &reporting.GRPCEvent_User{
+				User: &reporting.GRPCUserEvent{
+					Version:   e.Version,
+					Metadata:  toGRPCMetadata(e.Metadata),
+					ID:        e.Spec.ID,
+					Action:    e.Spec.Action,
+					AccountID: e.Spec.AccountID,
+					UserID:    e.Spec.UserID,
+				},
+			},
+		}, nil
+	}
+	return nil, trace.BadParameter("event %q has no typed gRPC message",
+		event.GetName())
+}
+
+// ToGRPCHeartbeat converts provided heartbeat to the typed gRPC message
+func ToGRPCHeartbeat(h Heartbeat) *reporting.GRPCHeartbeat {
+	heartbeat := &reporting.GRPCHeartbeat{
+		Version:      h.Version,
+		Metadata:     toGRPCMetadata(h.Metadata),
+		Features:     h.Spec.Features,
+		PollInterval: int64(h.Spec.PollInterval),
+	}
+	if h.Spec.License != nil {
+		heartbeat.License = &reporting.GRPCLicenseStatus{
+			Status:  h.Spec.License.Status,
+			Expires: toUnixNano(h.Spec.License.Expires),
+		}
+	}
+	for _, n := range h.Spec.Notifications {
+		heartbeat.Notifications = append(heartbeat.Notifications,
+			&reporting.GRPCNotification{
+				Type:        n.Type,
+				Severity:    n.Severity,
+				Text:        n.Text,
+				HTML:        n.HTML,
+				ID:          n.ID,
+				NotBefore:   toUnixNano(n.NotBefore),
+				NotAfter:    toUnixNano(n.NotAfter),
+				Dismissible: n.Dismissible,
+				URL:         n.URL,
+			})
+	}
+	return heartbeat
+}
+
+// FromGRPCHeartbeat converts heartbeat from the typed gRPC message using
+// the provided validation rules, typed heartbeats are not signed and are
+// rejected if signatures are required
+func FromGRPCHeartbeat(h *reporting.GRPCHeartbeat, config ValidationConfig) (*Heartbeat, error) {
+	if h == nil {
+		return nil, trace.BadParameter("missing heartbeat")
+	}
+	if config.RequireSignedHeartbeats {
+		return nil, trace.AccessDenied("heartbeat is not signed")
+	}
+	metadata, err := fromGRPCMetadata(h.Metadata, "heartbeat")
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if err := checkTypedVersion(h.Version); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	heartbeat := &Heartbeat{
+		Kind:     KindHeartbeat,
+		Version:  ResourceVersion,
+		Metadata: *metadata,
+		Spec: HeartbeatSpec{
+			Features:     h.Features,
+			PollInterval: time.Duration(h.PollInterval),
+		},
+	}
+	if h.License != nil {
+		heartbeat.Spec.License = &LicenseStatus{
+			Status:  h.License.Status,
+			Expires: fromUnixNano(h.License.Expires),
+		}
+	}
+	for _, n := range h.Notifications {
+		if n == nil {
+			return nil, trace.BadParameter("missing notification")
+		}
+		heartbeat.Spec.Notifications = append(heartbeat.Spec.Notifications,
+			Notification{
+				Type:        n.Type,
+				Severity:    n.Severity,
+				Text:        n.Text,
+				HTML:        n.HTML,
+				ID:          n.ID,
+				NotBefore:   fromUnixNano(n.NotBefore),
+				NotAfter:    fromUnixNano(n.NotAfter),
+				Dismissible: n.Dismissible,
+				URL:         n.URL,
+			})
+	}
+	if err := sanitizeHeartbeat(heartbeat, config.StrictHTML); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if err := heartbeat.Validate(config); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return heartbeat, nil
+}
+
+// fromGRPCTypedEvent converts event from the typed format used by gRPC
+// server/client
+func fromGRPCTypedEvent(grpcEvent reporting.GRPCEvent) (Event, error) {
+	switch e := grpcEvent.Event.(type) {
+	case *reporting.GRPCEvent_Server:
+		if e.Server == nil {
+			return nil, trace.BadParameter("missing server event")
+		}
+		metadata, err := fromGRPCMetadata(e.Server.Metadata, EventTypeServer)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		if err := checkTypedVersion(e.Server.Version); err != nil {
+			return nil, trace.Wrap(err)
+		}
+		return &ServerEvent{
+			Kind:     KindEvent,
+			Version:  ResourceVersion,
+			Metadata: *metadata,
+			Spec: ServerEventSpec{
+				ID:        e.Server.ID,
+				Action:    e.Server.Action,
+				AccountID: e.Server.AccountID,
+				ServerID:  e.Server.ServerID,
+			},
+		}, nil
+	case *reporting.GRPCEvent_User:
+		if e.User == nil {
+			return nil, trace.BadParameter("missing user event")
+		}
+		metadata, err := fromGRPCMetadata(e.User.Metadata, EventTypeUser)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		if err := checkTypedVersion(e.User.Version); err != nil {
+			return nil, trace.Wrap(err)
+		}
+		return &UserEvent{
+			Kind:     KindEvent,
+			Version:  ResourceVersion,
+			Metadata: *metadata,
+			Spec: UserEventSpec{
+				ID:        e.User.ID,
+				Action:    e.User.Action,
+				AccountID: e.User.AccountID,
+				UserID:    e.User.UserID,
+			},
+		}, nil
+	}
+	return nil, trace.BadParameter("unsupported typed event %T", grpcEvent.Event)
+}
+
+// toGRPCMetadata converts resource metadata to the typed gRPC message
+func toGRPCMetadata(m Metadata) *reporting.GRPCMetadata {
+	return &reporting.GRPCMetadata{
+		Name:    m.Name,
+		Created: toUnixNano(m.Created),
+		Labels:  m.Labels,
+	}
+}
+
+// fromGRPCMetadata converts resource metadata from the typed gRPC message,
+// the name defaults to the provided one and must match it if set
+func fromGRPCMetadata(m *reporting.GRPCMetadata, name string) (*Metadata, error) {
+	if m == nil {
+		return nil, trace.BadParameter("missing metadata")
+	}
+	if m.Name != "" && m.Name != name {
+		return nil, trace.BadParameter("expected name %q, got %q", name, m.Name)
+	}
+	return &Metadata{
+		Name:    name,
+		Created: fromUnixNano(m.Created),
+		Labels:  m.Labels,
+	}, nil
+}
+
+// checkTypedVersion checks the version of the typed resource, typed
+// messages of all supported versions share the same fields, and
+// an empty version means the current one
+func checkTypedVersion(version string) error {
+	if version != "" && !IsSupportedVersion(version) {
+		return trace.Wrap(&VersionError{Version: version})
+	}
+	return nil
+}
+
+// toUnixNano converts time to Unix time in nanoseconds used by typed
+// messages, zero time is converted to zero
+func toUnixNano(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+// fromUnixNano converts Unix time in nanoseconds used by typed messages
+// to time, zero is converted to zero time
+func fromUnixNano(nanos int64) time.Time {
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos).UTC()
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2017 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package types
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/gravitational/trace"
+)
+
+// EventFactory returns a new empty event of a registered type that
+// event payloads are unmarshaled into
+type EventFactory func() Event
+
+// RegisterEvent registers an event type with the provided name, so it can
+// be decoded by FromGRPCEvent. The schema is the JSON schema of the event
+// spec, and the factory must return a pointer to a new event struct that
+// has kind, version, metadata and spec fields.
+//
+// RegisterEvent is meant to be called from package init functions and
+// panics if the name is already registered or the schema is invalid.
+func RegisterEvent(name, schema string, factory EventFactory) {
+	if err := registerEvent(name, schema, factory); err != nil {
+		panic(err)
+	}
+}
+
+// RegisteredEvents returns the sorted names of all registered event types
+func RegisteredEvents() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+	names := make([]string, 0, len(registry.events))
+	for name := range registry.events {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// registerEvent adds the event type to the registry
+func registerEvent(name, schema string, factory EventFactory) error {
+	if name == "" {
+		return trace.BadParameter("missing event name")
+	}
+	if factory == nil {
+		return trace.BadParameter("missing factory for event %q", name)
+	}
+	fullSchema := fmt.Sprintf(schemaTemplate, schema)
+	if _, err := compileSchema(fullSchema); err != nil {
+		return trace.Wrap(err, "invalid schema for event %q", name)
+	}
+	registry.Lock()
+	defer registry.Unlock()
+	if _, ok := registry.events[name]; ok {
+		return trace.AlreadyExists("event %q is already registered", name)
+	}
+	registry.events[name] = eventType{
+		schema:  fullSchema,
+		factory: factory,
+	}
+	return nil
+}
+
+// getEventType returns the registered event type with the provided name
+func getEventType(name string) (*eventType, error) {
+	registry.RLock()
+	defer registry.RUnlock()
+	eventType, ok := registry.events[name]
+	if !ok {
+		return nil, trace.BadParameter("unknown event type %q", name)
+	}
+	return &eventType, nil
+}
+
+// eventType is a registered event type
+type eventType struct {
+	// schema is the full event resource schema
+	schema string
+	// factory returns new events of this type
+	factory EventFactory
+}
+
+// registry holds registered event types
+var registry = struct {
+	sync.RWMutex
+	events map[string]eventType
+}{
+	events: make(map[string]eventType),
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2017 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package types
+
+import (
+	"bytes"
+	"net/url"
+	"strings"
+
+	"github.com/gravitational/trace"
+	"golang.org/x/net/html"
+)
+
+// SanitizeHTML returns the provided HTML with tags and attributes that
+// are not allowed removed, contents of script-like elements are removed
+// together with the elements
+func SanitizeHTML(in string) string {
+	out, _ := sanitizeHTML(in)
+	return out
+}
+
+// CheckHTML returns an error if the provided HTML contains tags or
+// attributes that are not allowed
+func CheckHTML(in string) error {
+	if _, removed := sanitizeHTML(in); removed {
+		return trace.BadParameter("HTML contains tags or attributes that are not allowed")
+	}
+	return nil
+}
+
+// sanitizeHeartbeat sanitizes HTML of the heartbeat notifications, or
+// rejects the heartbeat if it contains disallowed HTML in strict mode
+func sanitizeHeartbeat(h *Heartbeat, strict bool) error {
+	notifications := make([]Notification, len(h.Spec.Notifications))
+	for i, n := range h.Spec.Notifications {
+		sanitized, removed := sanitizeHTML(n.HTML)
+		if removed {
+			if strict {
+				return trace.BadParameter("notification %q HTML contains tags or attributes that are not allowed",
+					n.ID)
+			}
+			n.HTML = sanitized
+		}
+		notifications[i] = n
+	}
+	if len(notifications) != 0 {
+		h.Spec.Notifications = notifications
+	}
+	return nil
+}
+
+// sanitizeHTML removes tags and attributes that are not allowed from
+// the HTML and returns true if anything has been removed
+func sanitizeHTML(in string) (out string, removed bool) {
+	tokenizer := html.NewTokenizer(strings.NewReader(in))
+	var b bytes.Buffer
+	// skip is the depth of dropped elements the tokenizer is in
+	skip := 0
+	for {
+		tokenType := tokenizer.Next()
+		switch tokenType {
+		case html.ErrorToken:
+			// the tokenizer only returns io.EOF for string readers
+			return b.String(), removed
+		case html.TextToken:
+			if skip == 0 {
+				b.WriteString(html.EscapeString(string(tokenizer.Text())))
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			if droppedTags[token.Data] {
+				removed = true
+				if tokenType == html.StartTagToken {
+					skip++
+				}
+				continue
+			}
+			if skip > 0 {
+				continue
+			}
+			attrs, ok := allowedTags[token.Data]
+			if !ok {
+				removed = true
+				continue
+			}
+			var allowed []html.Attribute
+			for _, attr := range token.Attr {
+				if attr.Namespace != "" || !attrs[attr.Key] || !isSafeAttr(attr) {
+					removed = true
+					continue
+				}
+				allowed = append(allowed, attr)
+			}
+			token.Attr = allowed
+			b.WriteString(token.String())
+		case html.EndTagToken:
+			token := tokenizer.Token()
+			if droppedTags[token.Data] {
+				if skip > 0 {
+					skip--
+				}
+				continue
+			}
+			if skip > 0 {
+				continue
+			}
+			if _, ok := allowedTags[token.Data]; !ok {
+				removed = true
+				continue
+			}
+			b.WriteString(token.String())
+		default:
+			// comments and doctypes are never allowed
+			removed = true
+		}
+	}
+}
+
+// isSafeAttr returns true if the attribute value is safe, links must be
+// absolute and use one of the allowed schemes
+func isSafeAttr(attr html.Attribute) bool {
+	if attr.Key != "href" {
+		return true
+	}
+	u, err := url.Parse(strings.TrimSpace(attr.Val))
+	if err != nil {
+		return false
+	}
+	return allowedSchemes[u.Scheme]
+}
+
+// allowedTags maps allowed tags to their allowed attributes
+var allowedTags = map[string]map[string]bool{
+	"a":      {"href": true, "title": true},
+	"b":      {},
+	"br":     {},
+	"code":   {},
+	"div":    {},
+	"em":     {},
+	"i":      {},
+	"li":     {},
+	"ol":     {},
+	"p":      {},
+	"pre":    {},
+	"span":   {},
+	"strong": {},
+	"u":      {},
+	"ul":     {},
+}
+
+// droppedTags are tags that are removed together with their contents
+var droppedTags = map[string]bool{
+	"iframe":   true,
+	"noscript": true,
+	"object":   true,
+	"script":   true,
+	"style":    true,
+	"svg":      true,
+	"math":     true,
+	"template": true,
+	"textarea": true,
+	"title":    true,
+}
+
+// allowedSchemes are URL schemes allowed in links
+var allowedSchemes = map[string]bool{
+	"http":   true,
+	"https":  true,
+	"mailto": true,
+}
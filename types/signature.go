@@ -0,0 +1,186 @@
+/*
+Copyright 2017 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package types
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gravitational/reporting"
+
+	"github.com/gravitational/trace"
+	"golang.org/x/crypto/ed25519"
+)
+
+// SigningKey is the private key resources are signed with
+type SigningKey struct {
+	// ID identifies the key, it is sent with signatures so receivers
+	// can pick the matching public key during key rotation
+	ID string
+	// PrivateKey is the Ed25519 private key
+	PrivateKey ed25519.PrivateKey
+}
+
+// Check checks the signing key
+func (k SigningKey) Check() error {
+	if k.ID == "" {
+		return trace.BadParameter("missing signing key ID")
+	}
+	if strings.Contains(k.ID, signatureSeparator) {
+		return trace.BadParameter("signing key ID %q can't contain %q", k.ID, signatureSeparator)
+	}
+	if len(k.PrivateKey) != ed25519.PrivateKeySize {
+		return trace.BadParameter("invalid signing key %q", k.ID)
+	}
+	return nil
+}
+
+// Signature is a detached signature of marshaled resource bytes, it is
+// sent separately from the resource, for example in a header
+type Signature struct {
+	// KeyID is the ID of the key the resource was signed with
+	KeyID string
+	// Signature is the Ed25519 signature
+	Signature []byte
+}
+
+// String returns the signature encoded as key ID and base64-encoded
+// signature separated by a dot
+func (s Signature) String() string {
+	return fmt.Sprintf("%v%v%v", s.KeyID, signatureSeparator,
+		base64.RawURLEncoding.EncodeToString(s.Signature))
+}
+
+// ParseSignature parses the signature encoded by Signature.String
+func ParseSignature(in string) (*Signature, error) {
+	index := strings.LastIndex(in, signatureSeparator)
+	if index <= 0 {
+		return nil, trace.BadParameter("malformed signature %q", in)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(in[index+1:])
+	if err != nil {
+		return nil, trace.BadParameter("malformed signature %q", in)
+	}
+	return &Signature{
+		KeyID:     in[:index],
+		Signature: signature,
+	}, nil
+}
+
+// Sign returns the detached signature of the provided bytes
+func Sign(bytes []byte, key SigningKey) (*Signature, error) {
+	if err := key.Check(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &Signature{
+		KeyID:     key.ID,
+		Signature: ed25519.Sign(key.PrivateKey, bytes),
+	}, nil
+}
+
+// MarshalSignedHeartbeat marshals heartbeat with schema validation and
+// returns it along with its detached signature
+func MarshalSignedHeartbeat(h Heartbeat, key SigningKey) ([]byte, *Signature, error) {
+	bytes, err := MarshalHeartbeat(h)
+	if err != nil {
+		return nil, nil, trace.Wrap(err)
+	}
+	signature, err := Sign(bytes, key)
+	if err != nil {
+		return nil, nil, trace.Wrap(err)
+	}
+	return bytes, signature, nil
+}
+
+// UnmarshalSignedHeartbeat verifies the heartbeat signature with the
+// heartbeat keys of the provided validation rules and unmarshals heartbeat
+// with schema validation, the signature can be nil unless signatures are
+// required, in which case heartbeats older than the max heartbeat age are
+// rejected as well
+func UnmarshalSignedHeartbeat(bytes []byte, signature *Signature, config ValidationConfig) (*Heartbeat, error) {
+	if signature == nil {
+		if config.RequireSignedHeartbeats {
+			return nil, trace.AccessDenied("heartbeat is not signed")
+		}
+	} else if err := verifySignature(bytes, *signature, config.HeartbeatKeys); err != nil {
+		return nil, trace.Wrap(err, "failed to verify heartbeat")
+	}
+	heartbeat, err := unmarshalHeartbeat(bytes, config)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if config.RequireSignedHeartbeats {
+		maxAge := config.maxHeartbeatAge()
+		if heartbeat.Metadata.Created.Before(time.Now().Add(-maxAge)) {
+			return nil, trace.AccessDenied("heartbeat created at %v is older than %v",
+				heartbeat.Metadata.Created, maxAge)
+		}
+	}
+	return heartbeat, nil
+}
+
+// SignGRPCEvent signs the JSON payload of the event, the signature
+// covers the exact payload bytes so the payload must not be re-encoded
+// after signing
+func SignGRPCEvent(grpcEvent *reporting.GRPCEvent, key SigningKey) error {
+	if len(grpcEvent.Data) == 0 {
+		return trace.BadParameter("only JSON-encoded events can be signed")
+	}
+	signature, err := Sign(grpcEvent.Data, key)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	grpcEvent.KeyID = signature.KeyID
+	grpcEvent.Signature = signature.Signature
+	return nil
+}
+
+// VerifyGRPCEvent verifies the signature of the JSON payload of the event
+// with the matching public key
+func VerifyGRPCEvent(grpcEvent reporting.GRPCEvent, keys map[string]ed25519.PublicKey) error {
+	if len(grpcEvent.Signature) == 0 {
+		return trace.AccessDenied("event is not signed")
+	}
+	err := verifySignature(grpcEvent.Data, Signature{
+		KeyID:     grpcEvent.KeyID,
+		Signature: grpcEvent.Signature,
+	}, keys)
+	if err != nil {
+		return trace.Wrap(err, "failed to verify event")
+	}
+	return nil
+}
+
+// verifySignature verifies the signature of the provided bytes with
+// the matching public key
+func verifySignature(bytes []byte, signature Signature, keys map[string]ed25519.PublicKey) error {
+	key, ok := keys[signature.KeyID]
+	if !ok || len(key) != ed25519.PublicKeySize {
+		return trace.AccessDenied("signed with unknown key %q", signature.KeyID)
+	}
+	if !ed25519.Verify(key, bytes, signature.Signature) {
+		return trace.AccessDenied("signature verification failed")
+	}
+	return nil
+}
+
+const (
+	// signatureSeparator separates key ID and signature in encoded signatures
+	signatureSeparator = "."
+)
@@ -0,0 +1,143 @@
+/*
+Copyright 2017 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package types
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gravitational/trace"
+)
+
+// SnapshotEvent represents a periodic report of the installation state,
+// such as number of nodes or users. Unlike other events snapshots are
+// gauges: only the latest snapshot of an account is meaningful.
+type SnapshotEvent struct {
+	// Kind is resource kind, for events it is "event"
+	Kind string `json:"kind"`
+	// Version is the event resource version
+	Version string `json:"version"`
+	// Metadata is the event metadata
+	Metadata Metadata `json:"metadata"`
+	// Spec is the event spec
+	Spec SnapshotEventSpec `json:"spec"`
+}
+
+// SnapshotEventSpec is snapshot event specification
+type SnapshotEventSpec struct {
+	// ID is event ID, may be used for de-duplication
+	ID string `json:"id"`
+	// AccountID is ID of account the snapshot was taken for
+	AccountID string `json:"accountID"`
+	// ProductVersion is the version of the reporting product
+	ProductVersion string `json:"productVersion"`
+	// Gauges are the reported values keyed by gauge name, such as
+	// "users" or "nodes.role.proxy"
+	Gauges map[string]int64 `json:"gauges,omitempty"`
+}
+
+// NewSnapshotEvent creates an instance of a snapshot event
+func NewSnapshotEvent(productVersion string, gauges map[string]int64) *SnapshotEvent {
+	return &SnapshotEvent{
+		Kind:    KindEvent,
+		Version: ResourceVersion,
+		Metadata: Metadata{
+			Name:    EventTypeSnapshot,
+			Created: time.Now().UTC(),
+		},
+		Spec: SnapshotEventSpec{
+			ID:             uuid.New().String(),
+			ProductVersion: productVersion,
+			Gauges:         gauges,
+		},
+	}
+}
+
+// GaugeNodesByRole returns the name of the gauge with number of nodes
+// that have the provided role
+func GaugeNodesByRole(role string) string {
+	return fmt.Sprintf("%v.role.%v", GaugeNodes, role)
+}
+
+// GetName returns the event name
+func (e *SnapshotEvent) GetName() string { return e.Metadata.Name }
+
+// GetMetadata returns the event metadata
+func (e *SnapshotEvent) GetMetadata() Metadata { return e.Metadata }
+
+// GetID returns the event ID
+func (e *SnapshotEvent) GetID() string { return e.Spec.ID }
+
+// GetAction returns the event action, snapshot events do not have one
+func (e *SnapshotEvent) GetAction() string { return "" }
+
+// GetAccountID returns the event account ID
+func (e *SnapshotEvent) GetAccountID() string { return e.Spec.AccountID }
+
+// SetAccountID sets the event account ID
+func (e *SnapshotEvent) SetAccountID(id string) {
+	e.Spec.AccountID = id
+}
+
+// SetLabel sets the event metadata label
+func (e *SnapshotEvent) SetLabel(key, value string) {
+	e.Metadata.SetLabel(key, value)
+}
+
+// Validate checks the event values
+func (e *SnapshotEvent) Validate(config ValidationConfig) error {
+	if err := validateID(e.Spec.ID); err != nil {
+		return trace.Wrap(err)
+	}
+	if err := config.validateString("account ID", e.Spec.AccountID, false); err != nil {
+		return trace.Wrap(err)
+	}
+	if err := config.validateString("product version", e.Spec.ProductVersion, true); err != nil {
+		return trace.Wrap(err)
+	}
+	for name, value := range e.Spec.Gauges {
+		if value < 0 {
+			return trace.BadParameter("gauge %q is negative", name)
+		}
+	}
+	return nil
+}
+
+func init() {
+	RegisterEvent(EventTypeSnapshot, snapshotEventSchema, func() Event { return &SnapshotEvent{} })
+}
+
+// snapshotEventSchema is the snapshot event spec schema
+const snapshotEventSchema = `{
+  "type": "object",
+  "additionalProperties": false,
+  "required": ["id", "accountID", "productVersion"],
+  "properties": {
+    "id": {"type": "string"},
+    "accountID": {"type": "string"},
+    "productVersion": {"type": "string"},
+    "gauges": {
+      "type": "object",
+      "maxProperties": 256,
+      "additionalProperties": false,
+      "patternProperties": {
+        "^[a-zA-Z0-9_./:-]{1,128}$": {"type": "integer", "minimum": 0}
+      }
+    }
+  }
+}`
@@ -17,8 +17,18 @@ limitations under the License.
 package types
 
 import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/gravitational/reporting"
+
+	"github.com/google/uuid"
+	"github.com/gravitational/trace"
+	"golang.org/x/crypto/ed25519"
 	check "gopkg.in/check.v1"
 )
 
@@ -42,12 +52,26 @@ func (s *TypesSuite) TestHeartbeat(c *check.C) {
 			Text:     "Terms of service violation",
 			HTML:     "<div>Terms of service violation</div>",
 		})
+	h.Spec.License = &LicenseStatus{
+		Status:  LicenseStatusActive,
+		Expires: time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC),
+	}
+	h.Spec.Features = []string{"sso"}
+	h.Spec.PollInterval = time.Hour
 	bytes, err := MarshalHeartbeat(*h)
 	c.Assert(err, check.IsNil)
 	unmarshaled, err := UnmarshalHeartbeat(bytes)
 	c.Assert(err, check.IsNil)
 	c.Assert(len(unmarshaled.Spec.Notifications), check.Equals, 2)
 	c.Assert(unmarshaled, check.DeepEquals, h)
+	c.Assert(unmarshaled.HasFeature("sso"), check.Equals, true)
+	c.Assert(unmarshaled.HasFeature("audit"), check.Equals, false)
+	typed, err := FromGRPCHeartbeat(ToGRPCHeartbeat(*h), ValidationConfig{})
+	c.Assert(err, check.IsNil)
+	c.Assert(typed, check.DeepEquals, h)
+
+	h.Spec.License.Status = "unknown"
+	c.Assert(trace.IsBadParameter(h.Validate(ValidationConfig{})), check.Equals, true)
 }
 
 func (s *TypesSuite) TestEmptyHeartbeat(c *check.C) {
@@ -59,3 +83,503 @@ func (s *TypesSuite) TestEmptyHeartbeat(c *check.C) {
 	c.Assert(len(unmarshaled.Spec.Notifications), check.Equals, 0)
 	c.Assert(unmarshaled, check.DeepEquals, h)
 }
+
+func (s *TypesSuite) TestRegisterEvent(c *check.C) {
+	c.Assert(RegisteredEvents(), check.DeepEquals, []string{
+		EventTypeAction, EventTypeConsent, EventTypeServer, testSessionEventType, EventTypeSnapshot,
+		EventTypeUsage, EventTypeUser})
+	event := &testSessionEvent{
+		Kind:    KindEvent,
+		Version: ResourceVersion,
+		Metadata: Metadata{
+			Name:    testSessionEventType,
+			Created: time.Now().UTC(),
+		},
+		Spec: testSessionEventSpec{
+			ID:        uuid.New().String(),
+			AccountID: "account",
+			SessionID: uuid.New().String(),
+		},
+	}
+	grpcEvent, err := ToGRPCEvent(event)
+	c.Assert(err, check.IsNil)
+	decoded, err := FromGRPCEvent(*grpcEvent)
+	c.Assert(err, check.IsNil)
+	c.Assert(decoded, check.DeepEquals, event)
+	// spec is validated against the registered schema
+	event.Spec.SessionID = ""
+	grpcEvent, err = ToGRPCEvent(event)
+	c.Assert(err, check.IsNil)
+	_, err = FromGRPCEvent(*grpcEvent)
+	c.Assert(err, check.NotNil)
+
+	err = registerEvent(EventTypeUser, userEventSchema, func() Event { return &UserEvent{} })
+	c.Assert(trace.IsAlreadyExists(err), check.Equals, true)
+	c.Assert(registerEvent("app.access", "{", func() Event { return &UserEvent{} }), check.NotNil)
+	c.Assert(registerEvent("app.access", userEventSchema, nil), check.NotNil)
+	_, err = FromGRPCEvent(reporting.GRPCEvent{Data: []byte(
+		`{"kind": "event", "version": "v2", "metadata": {"name": "app.access"}}`)})
+	c.Assert(trace.IsBadParameter(err), check.Equals, true)
+}
+
+func (s *TypesSuite) TestActionEvent(c *check.C) {
+	event := NewActionEvent("user", uuid.New().String(), EventActionCreate, map[string]string{
+		"resource": "cluster",
+		"provider": "aws",
+	})
+	event.SetAccountID("account")
+	grpcEvent, err := ToGRPCEvent(event)
+	c.Assert(err, check.IsNil)
+	decoded, err := FromGRPCEvent(*grpcEvent)
+	c.Assert(err, check.IsNil)
+	c.Assert(decoded, check.DeepEquals, event)
+
+	tooMany := make(map[string]string)
+	for i := 0; i <= MaxLabels; i++ {
+		tooMany[fmt.Sprintf("label%v", i)] = "value"
+	}
+	for _, labels := range []map[string]string{
+		tooMany,
+		{"resource": strings.Repeat("a", MaxLabelValueLength+1)},
+		{strings.Repeat("a", MaxLabelKeyLength+1): "value"},
+		{"invalid key": "value"},
+	} {
+		event := NewActionEvent("user", uuid.New().String(), EventActionUse, labels)
+		grpcEvent, err := ToGRPCEvent(event)
+		c.Assert(err, check.IsNil)
+		_, err = FromGRPCEvent(*grpcEvent)
+		c.Assert(err, check.NotNil)
+	}
+}
+
+func (s *TypesSuite) TestUsageEvent(c *check.C) {
+	start := time.Date(2017, 10, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	event := NewUsageEvent("account", "server-1", "session.duration", "12.5", "minutes", start, end)
+	grpcEvent, err := ToGRPCEvent(event)
+	c.Assert(err, check.IsNil)
+	decoded, err := FromGRPCEvent(*grpcEvent)
+	c.Assert(err, check.IsNil)
+	c.Assert(decoded, check.DeepEquals, event)
+	// the same usage reported again gets the same ID
+	again := NewUsageEvent("account", "server-1", "session.duration", "12.5", "minutes", start, end)
+	c.Assert(again.Spec.ID, check.Equals, event.Spec.ID)
+	other := NewUsageEvent("account", "server-1", "session.duration", "3", "minutes", end, end.Add(time.Hour))
+	c.Assert(other.Spec.ID, check.Not(check.Equals), event.Spec.ID)
+	// the same source of another account is reported separately
+	otherAccount := NewUsageEvent("other", "server-1", "session.duration", "12.5", "minutes", start, end)
+	c.Assert(otherAccount.Spec.ID, check.Not(check.Equals), event.Spec.ID)
+
+	for _, quantity := range []string{"-1", "1e3", "", "1.", "NaN"} {
+		event := NewUsageEvent("account", "server-1", "bytes.transferred", quantity, "bytes", start, end)
+		grpcEvent, err := ToGRPCEvent(event)
+		c.Assert(err, check.IsNil)
+		_, err = FromGRPCEvent(*grpcEvent)
+		c.Assert(err, check.NotNil, check.Commentf("quantity %q", quantity))
+	}
+}
+
+func (s *TypesSuite) TestSnapshotEvent(c *check.C) {
+	event := NewSnapshotEvent("2.4.0", map[string]int64{
+		GaugeUsers:                10,
+		GaugeNodes:                5,
+		GaugeNodesByRole("proxy"): 2,
+		GaugeNodesByRole("node"):  3,
+	})
+	event.SetAccountID("account")
+	grpcEvent, err := ToGRPCEvent(event)
+	c.Assert(err, check.IsNil)
+	decoded, err := FromGRPCEvent(*grpcEvent)
+	c.Assert(err, check.IsNil)
+	c.Assert(decoded, check.DeepEquals, event)
+
+	event.Spec.Gauges[GaugeUsers] = -1
+	grpcEvent, err = ToGRPCEvent(event)
+	c.Assert(err, check.IsNil)
+	_, err = FromGRPCEvent(*grpcEvent)
+	c.Assert(err, check.NotNil)
+}
+
+func (s *TypesSuite) TestVersions(c *check.C) {
+	event := NewUserLoginEvent(uuid.New().String())
+	event.Metadata.Labels = map[string]string{"product": "teleport"}
+	// older versions are emitted without fields they do not support
+	grpcEvent, err := ToGRPCEventVersion(event, V2)
+	c.Assert(err, check.IsNil)
+	var raw map[string]interface{}
+	c.Assert(json.Unmarshal(grpcEvent.Data, &raw), check.IsNil)
+	c.Assert(raw["version"], check.Equals, V2)
+	c.Assert(raw["metadata"], check.Not(check.HasLen), 0)
+	_, ok := raw["metadata"].(map[string]interface{})["labels"]
+	c.Assert(ok, check.Equals, false)
+	// and upgraded to the current version when decoded
+	decoded, err := FromGRPCEvent(*grpcEvent)
+	c.Assert(err, check.IsNil)
+	event.Metadata.Labels = nil
+	c.Assert(decoded, check.DeepEquals, event)
+
+	_, err = ToGRPCEventVersion(event, "v1")
+	c.Assert(err, check.NotNil)
+	_, err = FromGRPCEvent(reporting.GRPCEvent{Data: []byte(strings.Replace(
+		string(grpcEvent.Data), `"v2"`, `"v1"`, 1))})
+	c.Assert(IsVersionError(err), check.Equals, true)
+	c.Assert(trace.IsBadParameter(err), check.Equals, true)
+
+	h := NewHeartbeat(Notification{
+		Type:     NotificationUsage,
+		Severity: SeverityInfo,
+		Text:     "Usage",
+		HTML:     "<div>Usage</div>",
+	})
+	bytes, err := MarshalHeartbeatVersion(*h, V2)
+	c.Assert(err, check.IsNil)
+	c.Assert(json.Unmarshal(bytes, &raw), check.IsNil)
+	c.Assert(raw["version"], check.Equals, V2)
+	unmarshaled, err := UnmarshalHeartbeat(bytes)
+	c.Assert(err, check.IsNil)
+	c.Assert(unmarshaled, check.DeepEquals, h)
+}
+
+func (s *TypesSuite) TestTypedEvents(c *check.C) {
+	serverEvent := NewServerLoginEvent("server")
+	serverEvent.SetAccountID("account")
+	serverEvent.Metadata.Labels = map[string]string{"env": "prod"}
+	userEvent := NewUserLoginEvent("user")
+	userEvent.SetAccountID("account")
+	for _, event := range []Event{serverEvent, userEvent} {
+		grpcEvent, err := ToGRPCTypedEvent(event)
+		c.Assert(err, check.IsNil)
+		c.Assert(grpcEvent.Data, check.IsNil)
+		data, err := grpcEvent.Marshal()
+		c.Assert(err, check.IsNil)
+		var wire reporting.GRPCEvent
+		c.Assert(wire.Unmarshal(data), check.IsNil)
+		decoded, err := FromGRPCEvent(wire)
+		c.Assert(err, check.IsNil)
+		c.Assert(decoded, check.DeepEquals, event)
+	}
+
+	_, err := ToGRPCTypedEvent(NewActionEvent("user", "id", EventActionCreate, nil))
+	c.Assert(trace.IsBadParameter(err), check.Equals, true)
+	_, err = FromGRPCEvent(reporting.GRPCEvent{Event: &reporting.GRPCEvent_User{
+		User: &reporting.GRPCUserEvent{Metadata: &reporting.GRPCMetadata{Name: EventTypeServer}},
+	}})
+	c.Assert(trace.IsBadParameter(err), check.Equals, true)
+	_, err = FromGRPCEvent(reporting.GRPCEvent{Event: &reporting.GRPCEvent_User{
+		User: &reporting.GRPCUserEvent{Version: "v1", Metadata: &reporting.GRPCMetadata{}},
+	}})
+	c.Assert(IsVersionError(err), check.Equals, true)
+
+	h := NewHeartbeat(Notification{
+		Type:     NotificationUsage,
+		Severity: SeverityInfo,
+		Text:     "Usage",
+		HTML:     "<div>Usage</div>",
+	})
+	unmarshaled, err := FromGRPCHeartbeat(ToGRPCHeartbeat(*h), ValidationConfig{})
+	c.Assert(err, check.IsNil)
+	c.Assert(unmarshaled, check.DeepEquals, h)
+}
+
+func (s *TypesSuite) TestValidation(c *check.C) {
+	now := time.Now().UTC()
+	for _, modify := range []func(*ServerEvent){
+		func(e *ServerEvent) { e.Spec.ServerID = "" },
+		func(e *ServerEvent) { e.Spec.ID = "1" },
+		func(e *ServerEvent) { e.Spec.Action = "reboot" },
+		func(e *ServerEvent) { e.Metadata.Created = time.Time{} },
+		func(e *ServerEvent) { e.Metadata.Created = now.Add(time.Hour) },
+		func(e *ServerEvent) { e.Spec.ServerID = strings.Repeat("a", DefaultMaxStringLength+1) },
+	} {
+		event := NewServerLoginEvent("server")
+		modify(event)
+		c.Assert(trace.IsBadParameter(ValidateEvent(event, ValidationConfig{})), check.Equals, true)
+		grpcEvent, err := ToGRPCEvent(event)
+		c.Assert(err, check.IsNil)
+		_, err = FromGRPCEvent(*grpcEvent)
+		c.Assert(trace.IsBadParameter(err), check.Equals, true)
+	}
+
+	usage := NewUsageEvent("account", "server", "session.duration", "1", "minutes", now, now.Add(-time.Hour))
+	c.Assert(trace.IsBadParameter(ValidateEvent(usage, ValidationConfig{})), check.Equals, true)
+	h := NewHeartbeat(Notification{Type: NotificationUsage, Severity: "fatal", Text: "Usage"})
+	c.Assert(trace.IsBadParameter(h.Validate(ValidationConfig{})), check.Equals, true)
+
+	// rules are configurable
+	config := ValidationConfig{
+		MaxClockSkew: 2 * time.Hour,
+		MaxAge:       time.Hour,
+		Actions:      []string{"reboot"},
+	}
+	c.Assert(config.CheckAndSetDefaults(), check.IsNil)
+	event := NewServerLoginEvent("server")
+	event.Spec.Action = "reboot"
+	event.Metadata.Created = now.Add(time.Hour)
+	c.Assert(ValidateEvent(event, config), check.IsNil)
+	event.Metadata.Created = now.Add(-2 * time.Hour)
+	c.Assert(trace.IsBadParameter(ValidateEvent(event, config)), check.Equals, true)
+	c.Assert(trace.IsBadParameter(ValidateEvent(NewServerLoginEvent("server"), config)), check.Equals, true)
+	c.Assert((&ValidationConfig{MaxAge: -1}).CheckAndSetDefaults(), check.NotNil)
+
+	// stored events are decoded without validating their age
+	grpcEvent, err := ToGRPCEvent(event)
+	c.Assert(err, check.IsNil)
+	decoded, err := DecodeGRPCEvent(*grpcEvent)
+	c.Assert(err, check.IsNil)
+	c.Assert(decoded, check.DeepEquals, event)
+
+	// action events allow any action
+	action := NewActionEvent("user", "alice", "role.assign", nil)
+	c.Assert(ValidateEvent(action, ValidationConfig{}), check.IsNil)
+	c.Assert(ValidateEvent(action, config), check.IsNil)
+}
+
+func (s *TypesSuite) TestNotificationLifecycle(c *check.C) {
+	now := time.Now().UTC().Round(time.Second)
+	n := Notification{
+		Type:        NotificationUsage,
+		Severity:    SeverityWarning,
+		Text:        "Usage limit is almost exceeded",
+		HTML:        "<div>Usage limit is almost exceeded</div>",
+		ID:          "usage-limit",
+		NotBefore:   now.Add(-time.Hour),
+		NotAfter:    now.Add(time.Hour),
+		Dismissible: true,
+		URL:         "https://example.com/billing",
+	}
+	h := NewHeartbeat(n)
+	bytes, err := MarshalHeartbeatVersion(*h, ResourceVersion)
+	c.Assert(err, check.IsNil)
+	unmarshaled, err := UnmarshalHeartbeat(bytes)
+	c.Assert(err, check.IsNil)
+	c.Assert(unmarshaled, check.DeepEquals, h)
+	typed, err := FromGRPCHeartbeat(ToGRPCHeartbeat(*h), ValidationConfig{})
+	c.Assert(err, check.IsNil)
+	c.Assert(typed, check.DeepEquals, h)
+
+	c.Assert(n.IsActive(now), check.Equals, true)
+	c.Assert(n.IsActive(now.Add(-2*time.Hour)), check.Equals, false)
+	c.Assert(n.IsActive(now.Add(time.Hour)), check.Equals, false)
+
+	// v2 clients do not allow lifecycle fields, heartbeats are marshaled
+	// for them by default
+	bytes, err = MarshalHeartbeatVersion(*h, V2)
+	c.Assert(err, check.IsNil)
+	c.Assert(strings.Contains(string(bytes), "usage-limit"), check.Equals, false)
+	defaultBytes, err := MarshalHeartbeat(*h)
+	c.Assert(err, check.IsNil)
+	c.Assert(string(defaultBytes), check.Equals, string(bytes))
+
+	for _, modify := range []func(*Notification){
+		func(n *Notification) { n.ID = "" },
+		func(n *Notification) { n.NotAfter = n.NotBefore },
+		func(n *Notification) { n.URL = "javascript:alert(1)" },
+	} {
+		invalid := n
+		modify(&invalid)
+		c.Assert(trace.IsBadParameter(invalid.Validate(ValidationConfig{})), check.Equals, true)
+	}
+}
+
+func (s *TypesSuite) TestSanitizeHTML(c *check.C) {
+	for _, tc := range []struct {
+		in  string
+		out string
+	}{
+		{in: `<div>Usage <b>limit</b> &amp; <a href="https://example.com" title="Billing">billing</a></div>`,
+			out: `<div>Usage <b>limit</b> &amp; <a href="https://example.com" title="Billing">billing</a></div>`},
+		{in: `<p onclick="alert(1)">text</p>`, out: `<p>text</p>`},
+		{in: `<script>alert(1)</script>text`, out: `text`},
+		{in: `<a href="javascript:alert(1)">link</a>`, out: `<a>link</a>`},
+		{in: `<a href=" JaVaScRiPt:alert(1)">link</a>`, out: `<a>link</a>`},
+		{in: `<img src="x" onerror="alert(1)">text`, out: `text`},
+		{in: `<svg><script>alert(1)</script></svg>text<!-- comment -->`, out: `text`},
+		{in: `<iframe src="https://example.com"></iframe>`, out: ``},
+	} {
+		comment := check.Commentf(tc.in)
+		c.Assert(SanitizeHTML(tc.in), check.Equals, tc.out, comment)
+		c.Assert(CheckHTML(tc.in) == nil, check.Equals, tc.in == tc.out, comment)
+	}
+
+	h := NewHeartbeat(Notification{
+		Type:     NotificationUsage,
+		Severity: SeverityWarning,
+		Text:     "Usage",
+		HTML:     `<div onmouseover="alert(1)">Usage</div>`,
+	})
+	bytes, err := MarshalHeartbeat(*h)
+	c.Assert(err, check.IsNil)
+	c.Assert(h.Spec.Notifications[0].HTML, check.Equals, `<div onmouseover="alert(1)">Usage</div>`)
+	unmarshaled, err := UnmarshalHeartbeat(bytes)
+	c.Assert(err, check.IsNil)
+	c.Assert(unmarshaled.Spec.Notifications[0].HTML, check.Equals, `<div>Usage</div>`)
+	// disallowed HTML coming from the wire is sanitized as well
+	bytes = []byte(strings.Replace(string(bytes), `\u003cdiv\u003e`, `\u003cdiv onclick=x\u003e`, 1))
+	c.Assert(strings.Contains(string(bytes), "onclick"), check.Equals, true)
+	unmarshaled, err = UnmarshalHeartbeat(bytes)
+	c.Assert(err, check.IsNil)
+	c.Assert(unmarshaled.Spec.Notifications[0].HTML, check.Equals, `<div>Usage</div>`)
+
+	strict := ValidationConfig{StrictHTML: true}
+	_, err = UnmarshalSignedHeartbeat(bytes, nil, strict)
+	c.Assert(trace.IsBadParameter(err), check.Equals, true)
+	_, err = FromGRPCHeartbeat(ToGRPCHeartbeat(*h), strict)
+	c.Assert(trace.IsBadParameter(err), check.Equals, true)
+}
+
+func (s *TypesSuite) TestSignedHeartbeat(c *check.C) {
+	oldPublic, oldPrivate, err := ed25519.GenerateKey(rand.Reader)
+	c.Assert(err, check.IsNil)
+	newPublic, newPrivate, err := ed25519.GenerateKey(rand.Reader)
+	c.Assert(err, check.IsNil)
+	// both keys are trusted during rotation
+	config := ValidationConfig{
+		HeartbeatKeys: map[string]ed25519.PublicKey{
+			"old": oldPublic,
+			"new": newPublic,
+		},
+		RequireSignedHeartbeats: true,
+	}
+	c.Assert(config.CheckAndSetDefaults(), check.IsNil)
+
+	h := NewHeartbeat(Notification{
+		Type:     NotificationTerms,
+		Severity: SeverityError,
+		Text:     "Terms of service violation",
+	})
+	for _, key := range []SigningKey{{ID: "old", PrivateKey: oldPrivate}, {ID: "new", PrivateKey: newPrivate}} {
+		bytes, signature, err := MarshalSignedHeartbeat(*h, key)
+		c.Assert(err, check.IsNil)
+		parsed, err := ParseSignature(signature.String())
+		c.Assert(err, check.IsNil)
+		c.Assert(parsed, check.DeepEquals, signature)
+		unmarshaled, err := UnmarshalSignedHeartbeat(bytes, parsed, config)
+		c.Assert(err, check.IsNil)
+		c.Assert(unmarshaled, check.DeepEquals, h)
+	}
+
+	bytes, signature, err := MarshalSignedHeartbeat(*h, SigningKey{ID: "new", PrivateKey: newPrivate})
+	c.Assert(err, check.IsNil)
+	_, err = UnmarshalSignedHeartbeat(bytes, nil, config)
+	c.Assert(trace.IsAccessDenied(err), check.Equals, true)
+	_, err = FromGRPCHeartbeat(ToGRPCHeartbeat(*h), config)
+	c.Assert(trace.IsAccessDenied(err), check.Equals, true)
+	tampered := []byte(strings.Replace(string(bytes), "violation", "Violation", 1))
+	_, err = UnmarshalSignedHeartbeat(tampered, signature, config)
+	c.Assert(trace.IsAccessDenied(err), check.Equals, true)
+	_, err = UnmarshalSignedHeartbeat(bytes, &Signature{KeyID: "old", Signature: signature.Signature}, config)
+	c.Assert(trace.IsAccessDenied(err), check.Equals, true)
+	_, err = UnmarshalSignedHeartbeat(bytes, &Signature{KeyID: "unknown", Signature: signature.Signature}, config)
+	c.Assert(trace.IsAccessDenied(err), check.Equals, true)
+	_, err = ParseSignature("signature")
+	c.Assert(trace.IsBadParameter(err), check.Equals, true)
+	c.Assert((&ValidationConfig{RequireSignedHeartbeats: true}).CheckAndSetDefaults(), check.NotNil)
+
+	// old signed heartbeats can't be replayed
+	stale := *h
+	stale.Metadata.Created = time.Now().UTC().Add(-2 * DefaultMaxHeartbeatAge)
+	bytes, signature, err = MarshalSignedHeartbeat(stale, SigningKey{ID: "new", PrivateKey: newPrivate})
+	c.Assert(err, check.IsNil)
+	_, err = UnmarshalSignedHeartbeat(bytes, signature, config)
+	c.Assert(trace.IsAccessDenied(err), check.Equals, true)
+	config.MaxHeartbeatAge = 3 * DefaultMaxHeartbeatAge
+	_, err = UnmarshalSignedHeartbeat(bytes, signature, config)
+	c.Assert(err, check.IsNil)
+}
+
+func init() {
+	RegisterEvent(testSessionEventType, testSessionEventSchema, func() Event { return &testSessionEvent{} })
+}
+
+// testSessionEventType is the name of the custom test event
+const testSessionEventType = "session.start"
+
+// testSessionEvent is a custom event registered by tests
+type testSessionEvent struct {
+	Kind     string               `json:"kind"`
+	Version  string               `json:"version"`
+	Metadata Metadata             `json:"metadata"`
+	Spec     testSessionEventSpec `json:"spec"`
+}
+
+type testSessionEventSpec struct {
+	ID        string `json:"id"`
+	AccountID string `json:"accountID"`
+	SessionID string `json:"sessionID"`
+}
+
+func (e *testSessionEvent) GetName() string        { return e.Metadata.Name }
+func (e *testSessionEvent) GetMetadata() Metadata  { return e.Metadata }
+func (e *testSessionEvent) GetID() string          { return e.Spec.ID }
+func (e *testSessionEvent) GetAction() string      { return "" }
+func (e *testSessionEvent) GetAccountID() string   { return e.Spec.AccountID }
+func (e *testSessionEvent) SetAccountID(id string) { e.Spec.AccountID = id }
+
+// testSessionEventSchema is the custom test event spec schema
+const testSessionEventSchema = `{
+  "type": "object",
+  "additionalProperties": false,
+  "required": ["id", "accountID", "sessionID"],
+  "properties": {
+    "id": {"type": "string"},
+    "accountID": {"type": "string"},
+    "sessionID": {"type": "string", "minLength": 1}
+  }
+}`
+
+func BenchmarkFromGRPCEvents(b *testing.B) {
+	grpcEvents := newBenchmarkEvents(b, 1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := FromGRPCEvents(grpcEvents); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshalHeartbeat(b *testing.B) {
+	var notifications []Notification
+	for i := 0; i < 10; i++ {
+		notifications = append(notifications, Notification{
+			Type:     NotificationUsage,
+			Severity: SeverityWarning,
+			Text:     "Usage limit exceeded",
+			HTML:     "<div>Usage limit exceeded</div>",
+		})
+	}
+	bytes, err := MarshalHeartbeat(*NewHeartbeat(notifications...))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := UnmarshalHeartbeat(bytes); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// newBenchmarkEvents returns a batch of the provided number of gRPC events
+// of different types
+func newBenchmarkEvents(b *testing.B, count int) reporting.GRPCEvents {
+	var grpcEvents reporting.GRPCEvents
+	for i := 0; i < count; i++ {
+		var event Event
+		switch i % 3 {
+		case 0:
+			event = NewServerLoginEvent(uuid.New().String())
+		case 1:
+			event = NewUserLoginEvent(uuid.New().String())
+		case 2:
+			event = NewActionEvent("user", uuid.New().String(), EventActionCreate,
+				map[string]string{"resource": "cluster"})
+		}
+		event.SetAccountID(uuid.New().String())
+		grpcEvent, err := ToGRPCEvent(event)
+		if err != nil {
+			b.Fatal(err)
+		}
+		grpcEvents.Events = append(grpcEvents.Events, grpcEvent)
+	}
+	return grpcEvents
+}
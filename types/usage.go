@@ -0,0 +1,175 @@
+/*
+Copyright 2017 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package types
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gravitational/trace"
+)
+
+// UsageEvent represents metered consumption of a resource over a period
+// of time, such as session minutes or bytes transferred, used for billing
+type UsageEvent struct {
+	// Kind is resource kind, for events it is "event"
+	Kind string `json:"kind"`
+	// Version is the event resource version
+	Version string `json:"version"`
+	// Metadata is the event metadata
+	Metadata Metadata `json:"metadata"`
+	// Spec is the event spec
+	Spec UsageEventSpec `json:"spec"`
+}
+
+// UsageEventSpec is usage event specification
+type UsageEventSpec struct {
+	// ID is event ID. Unlike other events, usage event IDs must be
+	// deterministic: the same source of the same account reporting the
+	// same metric for the same period must always use the same ID, so
+	// retried or duplicated reports are de-duplicated by sinks and usage
+	// is never billed twice. Use UsageEventID to generate it.
+	ID string `json:"id"`
+	// AccountID is ID of account that consumed the resource, it is part
+	// of the event ID so it is set when the event is created
+	AccountID string `json:"accountID"`
+	// Metric is the name of the metered metric, such as "session.duration"
+	Metric string `json:"metric"`
+	// Quantity is the non-negative consumed amount as a decimal string,
+	// such as "12.5", it is not a float to avoid rounding errors in billing
+	Quantity string `json:"quantity"`
+	// Unit is the unit of the quantity, such as "minutes" or "bytes"
+	Unit string `json:"unit"`
+	// PeriodStart is the start of the aggregation period, inclusive
+	PeriodStart time.Time `json:"periodStart"`
+	// PeriodEnd is the end of the aggregation period, exclusive
+	PeriodEnd time.Time `json:"periodEnd"`
+}
+
+// NewUsageEvent creates an instance of a usage event for the provided
+// metric consumed by the source of the account during the period, the
+// source is anonymized ID of what reports usage, such as server ID
+func NewUsageEvent(accountID, source, metric, quantity, unit string, periodStart, periodEnd time.Time) *UsageEvent {
+	return &UsageEvent{
+		Kind:    KindEvent,
+		Version: ResourceVersion,
+		Metadata: Metadata{
+			Name:    EventTypeUsage,
+			Created: time.Now().UTC(),
+		},
+		Spec: UsageEventSpec{
+			ID:          UsageEventID(accountID, source, metric, periodStart, periodEnd),
+			AccountID:   accountID,
+			Metric:      metric,
+			Quantity:    quantity,
+			Unit:        unit,
+			PeriodStart: periodStart.UTC(),
+			PeriodEnd:   periodEnd.UTC(),
+		},
+	}
+}
+
+// UsageEventID returns deterministic ID of the usage event reported by
+// the source of the account for the provided metric and period, sources
+// only need to be unique within the account
+func UsageEventID(accountID, source, metric string, periodStart, periodEnd time.Time) string {
+	name := strings.Join([]string{
+		accountID,
+		source,
+		metric,
+		periodStart.UTC().Format(time.RFC3339Nano),
+		periodEnd.UTC().Format(time.RFC3339Nano),
+	}, "\x00")
+	return uuid.NewSHA1(usageNamespace, []byte(name)).String()
+}
+
+// GetName returns the event name
+func (e *UsageEvent) GetName() string { return e.Metadata.Name }
+
+// GetMetadata returns the event metadata
+func (e *UsageEvent) GetMetadata() Metadata { return e.Metadata }
+
+// GetID returns the event ID
+func (e *UsageEvent) GetID() string { return e.Spec.ID }
+
+// GetAction returns the event action, usage events do not have one
+func (e *UsageEvent) GetAction() string { return "" }
+
+// GetAccountID returns the event account ID
+func (e *UsageEvent) GetAccountID() string { return e.Spec.AccountID }
+
+// SetAccountID sets the event account ID
+func (e *UsageEvent) SetAccountID(id string) {
+	e.Spec.AccountID = id
+}
+
+// SetLabel sets the event metadata label
+func (e *UsageEvent) SetLabel(key, value string) {
+	e.Metadata.SetLabel(key, value)
+}
+
+// Validate checks the event values
+func (e *UsageEvent) Validate(config ValidationConfig) error {
+	if err := validateID(e.Spec.ID); err != nil {
+		return trace.Wrap(err)
+	}
+	if err := config.validateString("account ID", e.Spec.AccountID, false); err != nil {
+		return trace.Wrap(err)
+	}
+	if err := config.validateString("metric", e.Spec.Metric, true); err != nil {
+		return trace.Wrap(err)
+	}
+	if err := config.validateString("unit", e.Spec.Unit, true); err != nil {
+		return trace.Wrap(err)
+	}
+	if e.Spec.PeriodStart.IsZero() {
+		return trace.BadParameter("missing period start")
+	}
+	if !e.Spec.PeriodEnd.After(e.Spec.PeriodStart) {
+		return trace.BadParameter("period end %v is not after period start %v",
+			e.Spec.PeriodEnd, e.Spec.PeriodStart)
+	}
+	if e.Spec.PeriodEnd.After(time.Now().Add(config.maxClockSkew())) {
+		return trace.BadParameter("period end %v is in the future", e.Spec.PeriodEnd)
+	}
+	return nil
+}
+
+func init() {
+	RegisterEvent(EventTypeUsage, usageEventSchema, func() Event { return &UsageEvent{} })
+}
+
+// usageNamespace is the namespace of deterministic usage event IDs
+var usageNamespace = uuid.MustParse("6f1d7c3e-2b8a-4f57-9d0e-5c4a1b2e8f90")
+
+// usageEventSchema is the usage event spec schema, quantity must be
+// a non-negative decimal number
+const usageEventSchema = `{
+  "type": "object",
+  "additionalProperties": false,
+  "required": ["id", "accountID", "metric", "quantity", "unit", "periodStart", "periodEnd"],
+  "properties": {
+    "id": {"type": "string"},
+    "accountID": {"type": "string"},
+    "metric": {"type": "string", "minLength": 1},
+    "quantity": {"type": "string", "pattern": "^[0-9]+(\\.[0-9]+)?$"},
+    "unit": {"type": "string", "minLength": 1},
+    "periodStart": {"type": "string"},
+    "periodEnd": {"type": "string"}
+  }
+}`
@@ -0,0 +1,206 @@
+/*
+Copyright 2017 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package types
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gravitational/trace"
+	"golang.org/x/crypto/ed25519"
+)
+
+// Validator is implemented by resources that check their values beyond
+// the structure enforced by JSON schema
+type Validator interface {
+	// Validate returns an error if the resource values are invalid
+	// according to the provided rules
+	Validate(config ValidationConfig) error
+}
+
+// ValidationConfig defines semantic validation rules, unset rules
+// use default values
+type ValidationConfig struct {
+	// MaxClockSkew is how far in the future resource creation time can be
+	MaxClockSkew time.Duration
+	// MaxAge is how far in the past resource creation time can be,
+	// zero means resources of any age are accepted
+	MaxAge time.Duration
+	// MaxStringLength is the maximum length of string values
+	MaxStringLength int
+	// Actions is the set of allowed server and user event actions
+	Actions []string
+	// StrictHTML is whether heartbeats with notification HTML that is not
+	// allowed are rejected, by default disallowed HTML is removed
+	StrictHTML bool
+	// HeartbeatKeys are public keys heartbeat signatures are verified with
+	// keyed by key ID, multiple keys allow key rotation
+	HeartbeatKeys map[string]ed25519.PublicKey
+	// RequireSignedHeartbeats is whether unsigned heartbeats are rejected
+	RequireSignedHeartbeats bool
+	// MaxHeartbeatAge is how far in the past signed heartbeat creation time
+	// can be if signatures are required, it prevents replaying heartbeats
+	// that have been superseded
+	MaxHeartbeatAge time.Duration
+}
+
+// CheckAndSetDefaults checks the config and sets default values
+func (c *ValidationConfig) CheckAndSetDefaults() error {
+	if c.MaxClockSkew < 0 {
+		return trace.BadParameter("max clock skew can't be negative")
+	}
+	if c.MaxAge < 0 {
+		return trace.BadParameter("max age can't be negative")
+	}
+	if c.MaxStringLength < 0 {
+		return trace.BadParameter("max string length can't be negative")
+	}
+	if c.MaxHeartbeatAge < 0 {
+		return trace.BadParameter("max heartbeat age can't be negative")
+	}
+	for id, key := range c.HeartbeatKeys {
+		if len(key) != ed25519.PublicKeySize {
+			return trace.BadParameter("invalid heartbeat key %q", id)
+		}
+	}
+	if c.RequireSignedHeartbeats && len(c.HeartbeatKeys) == 0 {
+		return trace.BadParameter("signed heartbeats require heartbeat keys")
+	}
+	if c.MaxClockSkew == 0 {
+		c.MaxClockSkew = DefaultMaxClockSkew
+	}
+	if c.MaxStringLength == 0 {
+		c.MaxStringLength = DefaultMaxStringLength
+	}
+	if len(c.Actions) == 0 {
+		c.Actions = DefaultActions
+	}
+	if c.MaxHeartbeatAge == 0 {
+		c.MaxHeartbeatAge = DefaultMaxHeartbeatAge
+	}
+	return nil
+}
+
+// ValidateEvent checks the event metadata and, if the event implements
+// Validator, its values according to the provided rules
+func ValidateEvent(event Event, config ValidationConfig) error {
+	if err := config.validateMetadata(event.GetMetadata()); err != nil {
+		return trace.Wrap(err)
+	}
+	if validator, ok := event.(Validator); ok {
+		if err := validator.Validate(config); err != nil {
+			return trace.Wrap(err, "invalid %q event", event.GetName())
+		}
+	}
+	return nil
+}
+
+// validateMetadata checks the resource creation time and labels
+func (c ValidationConfig) validateMetadata(metadata Metadata) error {
+	if metadata.Created.IsZero() {
+		return trace.BadParameter("missing creation time")
+	}
+	now := time.Now()
+	if metadata.Created.After(now.Add(c.maxClockSkew())) {
+		return trace.BadParameter("creation time %v is in the future",
+			metadata.Created)
+	}
+	if c.MaxAge != 0 && metadata.Created.Before(now.Add(-c.MaxAge)) {
+		return trace.BadParameter("creation time %v is older than %v",
+			metadata.Created, c.MaxAge)
+	}
+	for key, value := range metadata.Labels {
+		if err := c.validateString("label "+key, value, false); err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	return nil
+}
+
+// maxClockSkew returns the maximum clock skew or the default value
+// if it is not set
+func (c ValidationConfig) maxClockSkew() time.Duration {
+	if c.MaxClockSkew <= 0 {
+		return DefaultMaxClockSkew
+	}
+	return c.MaxClockSkew
+}
+
+// maxHeartbeatAge returns the maximum signed heartbeat age or the default
+// value if it is not set
+func (c ValidationConfig) maxHeartbeatAge() time.Duration {
+	if c.MaxHeartbeatAge <= 0 {
+		return DefaultMaxHeartbeatAge
+	}
+	return c.MaxHeartbeatAge
+}
+
+// validateID checks that the event ID is a UUID
+func validateID(id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return trace.BadParameter("event ID %q is not a UUID", id)
+	}
+	return nil
+}
+
+// validateAction checks that the server or user event action is allowed
+func (c ValidationConfig) validateAction(action string) error {
+	actions := c.Actions
+	if len(actions) == 0 {
+		actions = DefaultActions
+	}
+	for _, allowed := range actions {
+		if action == allowed {
+			return nil
+		}
+	}
+	return trace.BadParameter("action %q is not allowed", action)
+}
+
+// validateString checks the length of the string value, and that the
+// value is set if it is required
+func (c ValidationConfig) validateString(name, value string, required bool) error {
+	if required && value == "" {
+		return trace.BadParameter("missing %v", name)
+	}
+	max := c.MaxStringLength
+	if max <= 0 {
+		max = DefaultMaxStringLength
+	}
+	if len(value) > max {
+		return trace.BadParameter("%v is longer than %v characters", name, max)
+	}
+	return nil
+}
+
+const (
+	// DefaultMaxClockSkew is the default maximum clock skew
+	DefaultMaxClockSkew = 10 * time.Minute
+	// DefaultMaxStringLength is the default maximum length of string values
+	DefaultMaxStringLength = 4096
+	// DefaultMaxHeartbeatAge is the default maximum age of signed heartbeats
+	DefaultMaxHeartbeatAge = 24 * time.Hour
+)
+
+// DefaultActions is the default set of allowed server and user event actions
+var DefaultActions = []string{
+	EventActionLogin,
+	EventActionLogout,
+	EventActionCreate,
+	EventActionDelete,
+	EventActionUse,
+}
@@ -0,0 +1,238 @@
+/*
+Copyright 2017 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/gravitational/trace"
+)
+
+// SupportedVersions lists resource versions that can be decoded, newest
+// first, older versions are upgraded to the current ResourceVersion
+var SupportedVersions = []string{V3, V2}
+
+// VersionError is returned for resources of unsupported versions, it is
+// recognized as a bad parameter error
+type VersionError struct {
+	// Version is the unsupported resource version
+	Version string
+}
+
+// Error returns the error message
+func (e *VersionError) Error() string {
+	return fmt.Sprintf("unsupported resource version %q, supported versions are %v",
+		e.Version, SupportedVersions)
+}
+
+// Unwrap returns the bad parameter error the version error is recognized as
+func (e *VersionError) Unwrap() error {
+	return &trace.BadParameterError{Message: e.Error()}
+}
+
+// IsVersionError returns true if the error is caused by an unsupported
+// resource version
+func IsVersionError(err error) bool {
+	var versionErr *VersionError
+	return errors.As(err, &versionErr)
+}
+
+// ConvertFunc converts raw resource payload between two adjacent
+// resource versions in place
+type ConvertFunc func(raw map[string]interface{}) error
+
+// RegisterUpgrade registers the function that converts resources of the
+// provided kind from the provided version to the next supported version.
+// It is meant to be called from package init functions and panics if
+// the conversion is already registered.
+func RegisterUpgrade(kind, from string, fn ConvertFunc) {
+	registerConversion(conversions.upgrades, kind, from, fn)
+}
+
+// RegisterDowngrade registers the function that converts resources of the
+// provided kind from the next supported version to the provided version.
+// It is meant to be called from package init functions and panics if
+// the conversion is already registered.
+func RegisterDowngrade(kind, to string, fn ConvertFunc) {
+	registerConversion(conversions.downgrades, kind, to, fn)
+}
+
+// IsSupportedVersion returns true if resources of the provided version
+// can be decoded
+func IsSupportedVersion(version string) bool {
+	return versionIndex(version) != -1
+}
+
+// upgrade converts the resource payload of an older supported version
+// to the current resource version
+func upgrade(kind string, data []byte) ([]byte, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	version, _ := raw["version"].(string)
+	if version == "" {
+		return nil, trace.BadParameter("missing resource version")
+	}
+	index := versionIndex(version)
+	if index == -1 {
+		return nil, trace.Wrap(&VersionError{Version: version})
+	}
+	for i := index; i > 0; i-- {
+		fn, err := getConversion(conversions.upgrades, kind, SupportedVersions[i])
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		if err := fn(raw); err != nil {
+			return nil, trace.Wrap(err)
+		}
+		raw["version"] = SupportedVersions[i-1]
+	}
+	return json.Marshal(raw)
+}
+
+// downgrade converts the resource of the current version to the provided
+// older supported version
+func downgrade(kind, version string, resource interface{}) ([]byte, error) {
+	index := versionIndex(version)
+	if index == -1 {
+		return nil, trace.Wrap(&VersionError{Version: version})
+	}
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if index == 0 {
+		return data, nil
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	for i := 1; i <= index; i++ {
+		fn, err := getConversion(conversions.downgrades, kind, SupportedVersions[i])
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		if err := fn(raw); err != nil {
+			return nil, trace.Wrap(err)
+		}
+		raw["version"] = SupportedVersions[i]
+	}
+	return json.Marshal(raw)
+}
+
+// versionIndex returns the index of the version in the list of supported
+// versions, or -1 if the version is not supported
+func versionIndex(version string) int {
+	for i, supported := range SupportedVersions {
+		if supported == version {
+			return i
+		}
+	}
+	return -1
+}
+
+// registerConversion adds the conversion function to the provided registry
+func registerConversion(registry map[conversionKey]ConvertFunc, kind, version string, fn ConvertFunc) {
+	conversions.Lock()
+	defer conversions.Unlock()
+	key := conversionKey{kind: kind, version: version}
+	if _, ok := registry[key]; ok {
+		panic(trace.AlreadyExists("conversion of %v %v is already registered", kind, version))
+	}
+	registry[key] = fn
+}
+
+// getConversion returns the conversion function from the provided registry
+func getConversion(registry map[conversionKey]ConvertFunc, kind, version string) (ConvertFunc, error) {
+	conversions.RLock()
+	defer conversions.RUnlock()
+	fn, ok := registry[conversionKey{kind: kind, version: version}]
+	if !ok {
+		return nil, trace.NotImplemented("no conversion of %v %v", kind, version)
+	}
+	return fn, nil
+}
+
+// conversionKey identifies a conversion between resource versions
+type conversionKey struct {
+	// kind is the resource kind
+	kind string
+	// version is the older of the two converted versions
+	version string
+}
+
+// conversions holds registered conversions between resource versions
+var conversions = struct {
+	sync.RWMutex
+	upgrades   map[conversionKey]ConvertFunc
+	downgrades map[conversionKey]ConvertFunc
+}{
+	upgrades:   make(map[conversionKey]ConvertFunc),
+	downgrades: make(map[conversionKey]ConvertFunc),
+}
+
+func init() {
+	for _, kind := range []string{KindEvent, KindHeartbeat} {
+		RegisterUpgrade(kind, V2, upgradeV2)
+	}
+	RegisterDowngrade(KindEvent, V2, downgradeV3)
+	RegisterDowngrade(KindHeartbeat, V2, downgradeHeartbeatV3)
+}
+
+// upgradeV2 converts v2 resources to v3, v3 only adds optional fields
+func upgradeV2(raw map[string]interface{}) error {
+	return nil
+}
+
+// downgradeV3 converts v3 resources to v2 by removing metadata labels
+// that v2 schema does not allow
+func downgradeV3(raw map[string]interface{}) error {
+	if metadata, ok := raw["metadata"].(map[string]interface{}); ok {
+		delete(metadata, "labels")
+	}
+	return nil
+}
+
+// downgradeHeartbeatV3 converts v3 heartbeats to v2 by removing metadata
+// labels and notification fields that v2 schema does not allow
+func downgradeHeartbeatV3(raw map[string]interface{}) error {
+	if err := downgradeV3(raw); err != nil {
+		return trace.Wrap(err)
+	}
+	spec, ok := raw["spec"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	notifications, _ := spec["notifications"].([]interface{})
+	for _, n := range notifications {
+		if notification, ok := n.(map[string]interface{}); ok {
+			for key := range notification {
+				switch key {
+				case "type", "severity", "text", "html":
+				default:
+					delete(notification, key)
+				}
+			}
+		}
+	}
+	return nil
+}